@@ -1,8 +1,10 @@
-package main
+package chess
 
 import (
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
 	"strconv"
 	"strings"
 	"unicode"
@@ -14,6 +16,29 @@ const (
 	StartingFEN = "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1"
 )
 
+// GameResult signifies what's happening in the game.
+type GameResult int
+
+const (
+	InProgress GameResult = iota
+	Draw
+	WhiteIsMated
+	BlackIsMated
+)
+
+// String returns the result as it's written in PGN.
+func (r GameResult) String() string {
+	switch r {
+	case Draw:
+		return "1/2-1/2"
+	case WhiteIsMated:
+		return "0-1"
+	case BlackIsMated:
+		return "1-0"
+	}
+	return "*"
+}
+
 type Spaces [64]Piece
 
 // BoardState contains the state of the board that's Undoable.
@@ -23,6 +48,8 @@ type BoardState struct {
 	fullMove, halfMove int
 	epTarget           Coord
 	hash               Hash
+	pawnHash           Hash // Hash of just the pawns.
+	material           MaterialKey
 	score              Score
 
 	// State of the kings.
@@ -41,6 +68,12 @@ type Board struct {
 	moves    []Move
 	oldState []BoardState
 	seen     map[Hash]int
+	keys     *hashKeys
+	debug    bool // Check the invariants after every move.
+
+	// Castling rights a FEN gave without the king and rook in place, in the
+	// order of castles. They can never be used, so they aren't checked.
+	fenCastle [4]bool
 }
 
 // at returns the piece at the specified location or Empty if there is none.
@@ -60,9 +93,11 @@ func (b *Board) occupancy(color Piece) Bit {
 func (b *Board) set(p Piece, c Coord) {
 	idx := c.Idx()
 
-	// Update the score.
+	// Update the score and material.
 	b.state.score -= b.at(c).Score()
 	b.state.score += p.Score()
+	b.state.material -= b.at(c).materialKey()
+	b.state.material += p.materialKey()
 
 	// Update the hash.
 	hashP := p
@@ -70,7 +105,11 @@ func (b *Board) set(p Piece, c Coord) {
 		hashP = b.at(c)
 	}
 	if hashP != Empty {
-		b.state.hash ^= zLookups[hashP.HashIdx()+idx]
+		key := b.keys.pieces[hashP.HashIdx()+idx]
+		b.state.hash ^= key
+		if hashP.IsPawn() {
+			b.state.pawnHash ^= key
+		}
 	}
 
 	// Update the king's location.
@@ -146,6 +185,30 @@ func (b *Board) isDrawn() bool {
 	return !b.hasEnoughMaterialForMate()
 }
 
+// Outcome returns the result of the game if it's over by the rules, along with
+// the reason, eg "checkmate" or "threefold repetition".
+func (b *Board) Outcome() (GameResult, string) {
+	if len(b.PossibleMoves(nil)) == 0 {
+		if !b.IsCheck() {
+			return Draw, "stalemate"
+		}
+		if b.state.turn == White {
+			return WhiteIsMated, "checkmate"
+		}
+		return BlackIsMated, "checkmate"
+	}
+	if b.seen[b.ZHash()] >= 3 {
+		return Draw, "threefold repetition"
+	}
+	if b.state.halfMove >= 100 {
+		return Draw, "fifty-move rule"
+	}
+	if !b.hasEnoughMaterialForMate() {
+		return Draw, "insufficient material"
+	}
+	return InProgress, ""
+}
+
 // ZHash returns the Zobrist hash for this board state.
 func (b *Board) ZHash() Hash {
 	return b.state.hash
@@ -246,6 +309,16 @@ func (b *Board) IsCheck() bool {
 	return b.state.isCheck
 }
 
+// At returns the piece at the specified location, or Empty if there is none.
+func (b *Board) At(c Coord) Piece {
+	return b.at(c)
+}
+
+// Turn returns the color of the side to move.
+func (b *Board) Turn() Piece {
+	return b.state.turn
+}
+
 // KingLoc returns the king location for the given piece's color.
 func (b *Board) KingLoc(color Piece) Coord {
 	if color.Color() == White {
@@ -431,12 +504,9 @@ func (b *Board) GetMoves(moves []Move, c Coord) []Move {
 	}
 
 queenCheckRook:
-	for _, toPos := range pCheck.Moves(c, b.state.wOcc|b.state.bOcc) {
-		// If we'd overlap one our own pieces, skip it.
-		if p2 := b.at(toPos); p2 != Empty && p2.Color() == p.Color() {
-			continue
-		}
-
+	// Skip the squares with our own pieces.
+	for v := pCheck.Moves(c, b.state.wOcc|b.state.bOcc) &^ b.occupancy(p); v != 0; {
+		toPos := v.NextCoord()
 		move := Move{
 			p:    p,
 			to:   toPos,
@@ -518,26 +588,9 @@ func (b *Board) handleRookMoveOrCapture(c Coord) {
 	}
 }
 
-// zCastle returns the Zobrist hash for the given castle state.
-func (b *Board) zCastle() (v Hash) {
-	if b.state.wOO {
-		v ^= zWOO
-	}
-	if b.state.wOOO {
-		v ^= zWOOO
-	}
-	if b.state.bOO {
-		v ^= zBOO
-	}
-	if b.state.bOOO {
-		v ^= zBOOO
-	}
-	return v
-}
-
 // updateCastleState updates the castling state for a given move.
 func (b *Board) updateCastleState(m Move) {
-	b.state.hash ^= b.zCastle()
+	b.state.hash ^= b.keys.castleHash(b)
 	// If we're moving a king, we can't castle anymore.
 	if m.p.IsKing() {
 		if m.p.IsWhite() {
@@ -550,18 +603,13 @@ func (b *Board) updateCastleState(m Move) {
 	// If we're moving or capturing a rook, update the state.
 	b.handleRookMoveOrCapture(m.from)
 	b.handleRookMoveOrCapture(m.to)
-	b.state.hash ^= b.zCastle()
+	b.state.hash ^= b.keys.castleHash(b)
 }
 
 // updateEPTarget updates the enpassant target.
+// Its hash is updated in MakeMove, as Polyglot's depends on the pieces.
 func (b *Board) updateEPTarget(m Move) {
-	if b.state.epTarget != InvalidCoord {
-		b.state.hash ^= zLookups[zEP+b.state.epTarget.FileIdx()]
-	}
 	b.state.epTarget = epTarget(m)
-	if b.state.epTarget != InvalidCoord {
-		b.state.hash ^= zLookups[zEP+b.state.epTarget.FileIdx()]
-	}
 }
 
 // MakeMove applies the move, and updates all necessary Board state.
@@ -570,7 +618,7 @@ func (b *Board) MakeMove(m Move) {
 	b.oldState = append(b.oldState, b.state)
 
 	// Update turn variables, and board state.
-	b.state.hash ^= zLookups[zBlack]
+	b.state.hash ^= b.keys.epHash(b) ^ b.keys.turn
 	if b.state.turn == White {
 		b.state.turn = Black
 	} else {
@@ -614,10 +662,15 @@ func (b *Board) MakeMove(m Move) {
 		}
 		b.set(m.p, m.to)
 	}
+	b.state.hash ^= b.keys.epHash(b)
 
 	// Save off the move.
 	b.moves = append(b.moves, m)
 	b.seen[b.ZHash()] += 1
+
+	if b.debug {
+		b.checkMove(m)
+	}
 }
 
 // UnmakeMove undoes the last move.
@@ -656,6 +709,19 @@ func (b *Board) GetMove(from, to Coord) (Move, error) {
 	return Move{}, fmt.Errorf("invalid move: %v", Move{from: from, to: to})
 }
 
+// findLegalMove returns the legal move with the same from, to, and promotion
+// as the given move, with the rest of its state (captures, checks, etc)
+// filled in. Unlike isLegalMove, it doesn't assume the move is one the piece
+// could make.
+func (b *Board) findLegalMove(m Move) (Move, bool) {
+	for _, lm := range b.GetMoves(nil, m.from) {
+		if lm.to == m.to && lm.promotion.Colorless() == m.promotion.Colorless() {
+			return lm, true
+		}
+	}
+	return Move{}, false
+}
+
 func (b *Board) parseAlgebraic(m string) (Move, error) {
 	if len(m) != 4 && len(m) != 5 {
 		return Move{}, fmt.Errorf("invalid move: %q, len = %d", m, len(m))
@@ -682,19 +748,34 @@ func (b *Board) parseAlgebraic(m string) (Move, error) {
 	return Move{from: from, to: to, p: p, promotion: promotion}, nil
 }
 
+// ParseMove parses a move in long algebraic notation (eg, "e2e4", "e7e8q"),
+// and returns it if it's legal.
+func (b *Board) ParseMove(mStr string) (Move, error) {
+	move, err := b.parseAlgebraic(mStr)
+	if err != nil {
+		return Move{}, err
+	}
+	// Only pawns promote, so ignore the promotion on other pieces' moves.
+	if move.p.Colorless() != Pawn {
+		move.promotion = Empty
+	}
+	move, ok := b.findLegalMove(move)
+	if !ok {
+		return Move{}, fmt.Errorf("move wasn't legal: %q", mStr)
+	}
+	return move, nil
+}
+
 // ApplyStringMove applies a string move.
 func (b *Board) ApplyStringMove(mStr string) error {
 	if len(mStr) == 0 {
 		return nil
 	}
 
-	move, err := b.parseAlgebraic(mStr)
+	move, err := b.ParseMove(mStr)
 	if err != nil {
 		return err
 	}
-	if !b.isLegalMove(&move) {
-		return fmt.Errorf("move wasn't legal: %q", mStr)
-	}
 	b.MakeMove(move)
 	return nil
 }
@@ -709,90 +790,38 @@ func (b *Board) ApplyMoves(moves []string) error {
 	return nil
 }
 
-type perftHash struct {
-	h Hash
-	d int
-}
-
-type PerftVerbosity int
-
-const (
-	Quiet PerftVerbosity = iota
-	Verbose
-)
-
-// Perft calculates the number of possible moves at a given depth. It's quite
-// helpful debugging the move generation. Optionally, Perft will also print the
-// number of reachable moves for each valid move in the given board state.
-func (b *Board) Perft(origDepth int, verbosity PerftVerbosity) uint64 {
-	if origDepth == 0 {
-		return 0
-	}
-
-	// Prevents allocating space for moves at every depth.
-	moveQueue := make([][]Move, origDepth)
-
-	// Keep around a set of counts.
-	counts := make(map[perftHash]uint64, 1000000)
-
-	var perft func(int, bool) uint64
-	perft = func(d int, s bool) uint64 {
-		// Exit early.
-		moves := moveQueue[origDepth-d][:0]
-		moves = b.PossibleMoves(moves)
-		if d <= 1 {
-			if s {
-				for _, m := range moves {
-					fmt.Printf("%v: 1\n", m)
-				}
-			}
-			return uint64(len(moves))
-		}
-
-		// Haven't seen this position before, need to calculate it.
-		var total uint64
-		for _, move := range moves {
-			b.MakeMove(move)
-
-			var cnt uint64
-			h := perftHash{b.state.hash, d}
-			if v, ok := counts[h]; ok {
-				cnt = v
-			} else {
-				cnt = perft(d-1, false)
-				counts[h] = cnt
-			}
-
-			if s {
-				fmt.Printf("%v: %d\n", move, cnt)
-			}
-			b.UnmakeMove()
-			total += cnt
-		}
-		return total
+// Copy returns a copy of the board, which can be used independently of it.
+func (b *Board) Copy() *Board {
+	return &Board{
+		state:     b.state,
+		moves:     slices.Clone(b.moves),
+		oldState:  slices.Clone(b.oldState),
+		seen:      maps.Clone(b.seen),
+		keys:      b.keys,
+		debug:     b.debug,
+		fenCastle: b.fenCastle,
 	}
-
-	return perft(origDepth, verbosity == Verbose)
 }
 
 // EmptyBoard returns a new, empty board. No state of gameplay is set up.
-func EmptyBoard() *Board {
-	return &Board{
-		state: BoardState{
-			epTarget: InvalidCoord,
-			wkLoc:    InvalidCoord,
-			bkLoc:    InvalidCoord,
-			fullMove: 1,
-		},
+func EmptyBoard(opts ...BoardOption) *Board {
+	b := &Board{
 		oldState: make([]BoardState, 0, 200),
 		moves:    make([]Move, 0, 200),
 		seen:     make(map[Hash]int, 10000),
+		keys:     zobristKeys,
+		debug:    debugInvariants,
 	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	b.reset()
+	return b
 }
 
 // New returns a new Board, set up for play (ie a new chess game).
-func New() *Board {
-	b, err := FromFEN(StartingFEN)
+func New(opts ...BoardOption) *Board {
+	b, err := FromFEN(StartingFEN, opts...)
 	if err != nil {
 		panic(err)
 	}
@@ -825,13 +854,36 @@ func (b *Board) validate() error {
 }
 
 // FromFEN creates a Board from a FEN string.
-func FromFEN(s string) (*Board, error) {
-	b := EmptyBoard()
+func FromFEN(s string, opts ...BoardOption) (*Board, error) {
+	b := EmptyBoard(opts...)
+	if err := b.SetFEN(s); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// reset clears the board, and all its history.
+func (b *Board) reset() {
+	b.state = BoardState{
+		epTarget: InvalidCoord,
+		wkLoc:    InvalidCoord,
+		bkLoc:    InvalidCoord,
+		fullMove: 1,
+	}
+	b.moves = b.moves[:0]
+	b.oldState = b.oldState[:0]
+	clear(b.seen)
+}
+
+// SetFEN resets the board to the position in a FEN string. It allows a Board
+// to be reused when many positions need to be parsed.
+func (b *Board) SetFEN(s string) error {
+	b.reset()
 	coord := CoordFromXY(0, 7)
 	parts := strings.Fields(s)
 
 	if len(parts) != 6 {
-		return nil, fmt.Errorf("invalid number of FEN fields: %d", len(parts))
+		return fmt.Errorf("invalid number of FEN fields: %d", len(parts))
 	}
 
 	// Parse board.
@@ -844,7 +896,7 @@ func FromFEN(s string) (*Board, error) {
 			if p, ok := runeToPiece[c]; ok {
 				b.set(p, coord)
 			} else {
-				return nil, errors.New(fmt.Sprintf("didn't find %v", c))
+				return errors.New(fmt.Sprintf("didn't find %v", c))
 			}
 		}
 		coord = CoordFromXY(
@@ -873,45 +925,51 @@ func FromFEN(s string) (*Board, error) {
 		case '-':
 			continue
 		default:
-			return nil, errors.New(fmt.Sprintf("bad castling char: %c", c))
+			return errors.New(fmt.Sprintf("bad castling char: %c", c))
 		}
 	}
+	for i, can := range b.state.castleFlags() {
+		b.fenCastle[i] = can && !b.castlePieces(i)
+	}
 
 	// Parse en passant target.
 	if target, err := CoordFromString(parts[3]); err != nil {
-		return nil, fmt.Errorf("error parsing en passant target: %w", err)
+		return fmt.Errorf("error parsing en passant target: %w", err)
 	} else {
 		b.state.epTarget = target
 	}
 
 	// Parse the half move.
 	if m, err := strconv.Atoi(parts[4]); err != nil {
-		return nil, fmt.Errorf("error parsing half moves: %w", err)
+		return fmt.Errorf("error parsing half moves: %w", err)
 	} else if m < 0 {
-		return nil, fmt.Errorf("halfmove < 0: %d", m)
+		return fmt.Errorf("halfmove < 0: %d", m)
 	} else {
 		b.state.halfMove = m
 	}
 
 	// Parse the full move.
 	if m, err := strconv.Atoi(parts[5]); err != nil {
-		return nil, fmt.Errorf("error parsing full moves: %w", err)
+		return fmt.Errorf("error parsing full moves: %w", err)
 	} else {
 		b.state.fullMove = m
 	}
 
 	if err := b.validate(); err != nil {
-		return nil, err
+		return err
 	}
 
 	// Figure out if the king is in check.
 	b.state.isCheck = b.isSquareAttacked(b.KingLoc(b.state.turn),
 		b.occupancy(b.state.turn.OppositeColor()))
 
+	// set only hashed the pieces, so hash the full state.
+	b.state.hash = b.keys.hash(b)
+
 	// Save the state.
 	b.seen[b.ZHash()] += 1
 
-	return b, nil
+	return nil
 }
 
 // CurrentPlayerMaterial returns the score for the current player.
@@ -921,3 +979,13 @@ func (b *Board) CurrentPlayerMaterial() Score {
 	}
 	return -b.state.score
 }
+
+// Ply returns the number of half moves into the game, as counted from the full
+// move number.
+func (b *Board) Ply() int {
+	ply := 2 * (b.state.fullMove - 1)
+	if b.state.turn == Black {
+		ply += 1
+	}
+	return ply
+}
@@ -1,4 +1,4 @@
-package main
+package chess
 
 import (
 	"reflect"
@@ -574,6 +574,39 @@ func TestPerft(t *testing.T) {
 	}
 }
 
+func TestDivide(t *testing.T) {
+	tests := []struct {
+		fen   string
+		depth int
+		moves int
+		total uint64
+	}{
+		{StartingFEN, 1, 20, 20},
+		{StartingFEN, 3, 20, 8902},
+		{"r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1", 2, 48, 2039},
+		{"8/2p5/3p4/KP5r/1R3p1k/8/4P1P1/8 w - - 0 1", 3, 14, 2812},
+		{StartingFEN, 0, 0, 0},
+	}
+	for i, test := range tests {
+		b, err := FromFEN(test.fen)
+		if err != nil {
+			t.Fatalf("[%d] FromFEN(%q) = %v", i, test.fen, err)
+		}
+		counts := b.Divide(test.depth)
+		var total uint64
+		for _, cnt := range counts {
+			total += cnt
+		}
+		if len(counts) != test.moves || total != test.total {
+			t.Errorf("[%d] Divide(%d) = %d moves, %d nodes, expected %d, %d", i, test.depth, len(counts), total, test.moves, test.total)
+		}
+	}
+	b := New()
+	if v := b.Divide(2)["e2e4"]; v != 20 {
+		t.Errorf("Divide(2)[e2e4] = %d, expected 20", v)
+	}
+}
+
 func TestPossibleMoves(t *testing.T) {
 	coord := testingCoordFunc(t)
 	move := func(p Piece, from, to string) Move {
@@ -736,13 +769,33 @@ func TestHashDifference(t *testing.T) {
 }
 
 func TestHashSame(t *testing.T) {
-	b1 := New()
-	b2, err := FromFEN(StartingFEN)
-	if err != nil {
-		t.Fatalf("error making board from starting FEN: %v", err)
+	tests := []struct {
+		moves []string
+		fen   string
+	}{
+		{nil, StartingFEN},
+		{[]string{"e2e4"}, "rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1"},
+		{[]string{"e2e4", "e7e5", "e1e2", "e8e7"}, "rnbq1bnr/ppppkppp/8/4p3/4P3/8/PPPPKPPP/RNBQ1BNR w - - 2 3"},
+		{[]string{"e2e4", "d7d5", "e4e5", "f7f5"}, "rnbqkbnr/ppp1p1pp/8/3pPp2/8/8/PPPP1PPP/RNBQKBNR w KQkq f6 0 3"},
 	}
-	if h1, h2 := b1.ZHash(), b2.ZHash(); h1 != h2 {
-		t.Errorf("hash %x != %x", h1, h2)
+
+	for i, test := range tests {
+		for _, opts := range [][]BoardOption{nil, {WithPolyglotHashing()}} {
+			b1 := New(opts...)
+			if err := b1.ApplyMoves(test.moves); err != nil {
+				t.Fatalf("[%d] error applying moves: %v", i, err)
+			}
+			b2, err := FromFEN(test.fen, opts...)
+			if err != nil {
+				t.Fatalf("[%d] FromFEN(%q) = %v", i, test.fen, err)
+			}
+			if h1, h2 := b1.ZHash(), b2.ZHash(); h1 != h2 {
+				t.Errorf("[%d] hash %x != %x", i, h1, h2)
+			}
+			if h1, h2 := b1.ZHash(), b1.keys.hash(b1); h1 != h2 {
+				t.Errorf("[%d] incremental hash %x != %x", i, h1, h2)
+			}
+		}
 	}
 }
 
@@ -841,6 +894,37 @@ func TestIsDrawn(t *testing.T) {
 	}
 }
 
+func TestOutcome(t *testing.T) {
+	tests := []struct {
+		fen    string
+		moves  []string
+		result GameResult
+		reason string
+	}{
+		{StartingFEN, []string{"e2e4"}, InProgress, ""},
+		{StartingFEN, []string{"f2f3", "e7e5", "g2g4", "d8h4"}, WhiteIsMated, "checkmate"},
+		{"6k1/5ppp/8/8/8/8/8/R5K1 w - - 0 1", []string{"a1a8"}, BlackIsMated, "checkmate"},
+		{"k7/8/1Q6/8/8/8/8/K7 b - - 0 1", nil, Draw, "stalemate"},
+		{StartingFEN, []string{"b1c3", "b8c6", "c3b1", "c6b8", "b1c3", "b8c6", "c3b1", "c6b8"}, Draw, "threefold repetition"},
+		{strings.Replace(StartingFEN, "0 1", "99 1", 1), []string{"b1c3"}, Draw, "fifty-move rule"},
+		{strings.Replace(StartingFEN, "0 1", "49 1", 1), []string{"b1c3"}, InProgress, ""},
+		{"K7/8/8/8/8/8/8/k7 w - - 0 1", nil, Draw, "insufficient material"},
+	}
+
+	for i, test := range tests {
+		b, err := FromFEN(test.fen)
+		if err != nil {
+			t.Fatalf("[%d] error parsing board: %q – %v", i, test.fen, err)
+		}
+		if err := b.ApplyMoves(test.moves); err != nil {
+			t.Fatalf("[%d] error applying moves: %v", i, err)
+		}
+		if result, reason := b.Outcome(); result != test.result || reason != test.reason {
+			t.Errorf("[%d] Outcome() = %v, %q, expected %v, %q", i, result, reason, test.result, test.reason)
+		}
+	}
+}
+
 func BenchmarkPerft1(b *testing.B) {
 	board := New()
 	for n := 0; n < b.N; n++ {
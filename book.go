@@ -1,3 +0,0 @@
-package main
-
-//go:generate go run bit.go book.go book_gen.go coord.go dir.go magic.go magic_tables.go move.go piece.go piece_tables.go board.go zobrist_tables.go
@@ -0,0 +1,651 @@
+// Package book implements opening books: the built-in book, books built from
+// PGN games or text, and books in Polyglot's .bin format.
+package book
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"chess"
+	"chess/notation"
+)
+
+// The built-in book, in the text format read by Builder.AddText.
+//
+//go:embed book.txt
+var bookText string
+
+// Builtin returns the built-in book, building it on first use.
+var Builtin = sync.OnceValue(func() *Book {
+	bb := NewBuilder()
+	if err := bb.AddText(strings.NewReader(bookText)); err != nil {
+		panic(fmt.Sprintf("error reading built-in book: %v", err))
+	}
+	return bb.Book()
+})
+
+// Move is a candidate move from an opening book.
+type Move struct {
+	Move   chess.Move
+	Weight int
+}
+
+// Source is a source of book moves.
+type Source interface {
+	// Moves returns the legal book moves for a board.
+	Moves(b *chess.Board) []Move
+}
+
+// pickMove chooses one of the moves at random, weighted by the moves'
+// weights.
+func pickMove(moves []Move, r *rand.Rand) (chess.Move, bool) {
+	var total int
+	for _, m := range moves {
+		total += m.Weight
+	}
+	if total <= 0 {
+		return chess.Move{}, false
+	}
+	idx := r.Intn(total)
+	for _, m := range moves {
+		idx -= m.Weight
+		if idx < 0 {
+			return m.Move, true
+		}
+	}
+	panic("shouldn't be reachable")
+}
+
+// Mode determines how a move is chosen from a position's book moves.
+type Mode int
+
+const (
+	Weighted Mode = iota // At random, weighted by the moves' weights.
+	Best                 // The move with the highest weight.
+	Uniform              // At random, ignoring the weights.
+)
+
+var modeNames = []string{"Weighted", "Best", "Uniform"}
+
+func (m Mode) String() string {
+	if int(m) < len(modeNames) {
+		return modeNames[m]
+	}
+	return fmt.Sprintf("Mode(%d)", int(m))
+}
+
+// ParseMode returns the Mode with a given name.
+func ParseMode(s string) (Mode, error) {
+	for i, name := range modeNames {
+		if strings.EqualFold(s, name) {
+			return Mode(i), nil
+		}
+	}
+	return Weighted, fmt.Errorf("unknown book mode: %q", s)
+}
+
+// Selector chooses moves from an opening book.
+type Selector struct {
+	Mode      Mode
+	MinWeight int // Skip moves weighing less than MinWeight percent of the best move.
+	MaxPly    int // Stop using the book MaxPly plies into the game (0 means no limit).
+}
+
+// Pick chooses a book move for the board. Best is deterministic, and the
+// other modes use r.
+func (bs Selector) Pick(ob Source, b *chess.Board, r *rand.Rand) (chess.Move, bool) {
+	if bs.MaxPly != 0 && b.Ply() >= bs.MaxPly {
+		return chess.Move{}, false
+	}
+	return bs.choose(ob.Moves(b), r)
+}
+
+// choose picks one of the moves.
+func (bs Selector) choose(moves []Move, r *rand.Rand) (chess.Move, bool) {
+	best := -1
+	for i, m := range moves {
+		if m.Weight > 0 && (best == -1 || m.Weight > moves[best].Weight) {
+			best = i
+		}
+	}
+	if best == -1 {
+		return chess.Move{}, false
+	}
+
+	// Apply the cutoff.
+	cutoff := moves[best].Weight * bs.MinWeight
+	candidates := make([]Move, 0, len(moves))
+	for _, m := range moves {
+		if m.Weight > 0 && m.Weight*100 >= cutoff {
+			candidates = append(candidates, m)
+		}
+	}
+
+	switch bs.Mode {
+	case Best:
+		return moves[best].Move, true
+	case Uniform:
+		return candidates[r.Intn(len(candidates))].Move, true
+	}
+	return pickMove(candidates, r)
+}
+
+// shortMove is a book move, encoded as a Polyglot move.
+type shortMove struct {
+	count int
+	move  uint16
+}
+
+// Book is an opening book, keyed by the Polyglot hash of the position. Keying
+// by the Polyglot hash means that en passant targets only matter when a
+// capture is possible, so positions from FEN strings that omit the target
+// still match.
+type Book struct {
+	positions map[chess.Hash][]shortMove
+}
+
+// Len returns the number of positions in the book.
+func (bk *Book) Len() int {
+	return len(bk.positions)
+}
+
+// Moves returns the legal book moves for a board.
+func (bk *Book) Moves(b *chess.Board) []Move {
+	var moves []Move
+	for _, sm := range bk.positions[b.PolyglotKey()] {
+		m, err := decodePolyglotMove(b, sm.move)
+		if err != nil {
+			continue
+		}
+		moves = append(moves, Move{Move: m, Weight: sm.count})
+	}
+	return moves
+}
+
+// Pick chooses a book move, weighted by the number of times it was played.
+func (bk *Book) Pick(b *chess.Board, r *rand.Rand) (chess.Move, bool) {
+	return pickMove(bk.Moves(b), r)
+}
+
+// sortShortMoves sorts moves with the most popular first.
+func sortShortMoves(moves []shortMove) {
+	sort.Slice(moves, func(i, j int) bool {
+		if moves[i].count != moves[j].count {
+			return moves[i].count > moves[j].count
+		}
+		return moves[i].move < moves[j].move
+	})
+}
+
+// sortedKeys returns the book's keys in order.
+func (bk *Book) sortedKeys() []chess.Hash {
+	keys := make([]chess.Hash, 0, len(bk.positions))
+	for k := range bk.positions {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
+
+// Polyglot converts the book into a Polyglot book. Polyglot weights are 16
+// bits, so the counts are scaled per position to fit.
+func (bk *Book) Polyglot() *Polyglot {
+	pb := &Polyglot{}
+	for _, key := range bk.sortedKeys() {
+		moves := bk.positions[key]
+		maxCount := 1
+		for _, sm := range moves {
+			maxCount = max(maxCount, sm.count)
+		}
+		for _, sm := range moves {
+			pb.entries = append(pb.entries, PolyglotEntry{
+				Key:    key,
+				Move:   sm.move,
+				Weight: uint16(max(1, sm.count*0xFFFF/maxCount)),
+			})
+		}
+	}
+	return pb
+}
+
+// The compact binary book format is:
+//
+//	magic   [4]byte "GCBK"
+//	version uint16
+//	count   uint32
+//	count entries of:
+//		key   uint64
+//		move  uint16 (Polyglot encoding)
+//		count uint32
+//
+// All values are little endian, and entries are sorted by key.
+var bookMagic = []byte("GCBK")
+
+const bookVersion = 1
+
+// WriteTo writes the book in the compact binary format.
+func (bk *Book) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	buf.Write(bookMagic)
+	binary.Write(&buf, binary.LittleEndian, uint16(bookVersion))
+	var count uint32
+	for _, moves := range bk.positions {
+		count += uint32(len(moves))
+	}
+	binary.Write(&buf, binary.LittleEndian, count)
+	for _, key := range bk.sortedKeys() {
+		for _, sm := range bk.positions[key] {
+			binary.Write(&buf, binary.LittleEndian, uint64(key))
+			binary.Write(&buf, binary.LittleEndian, sm.move)
+			binary.Write(&buf, binary.LittleEndian, uint32(sm.count))
+		}
+	}
+	return buf.WriteTo(w)
+}
+
+// Read reads a book in the compact binary format.
+func Read(r io.Reader) (*Book, error) {
+	br := bufio.NewReader(r)
+	magic := make([]byte, len(bookMagic))
+	if _, err := io.ReadFull(br, magic); err != nil || !bytes.Equal(magic, bookMagic) {
+		return nil, errors.New("not a book file")
+	}
+	var version uint16
+	var count uint32
+	if err := binary.Read(br, binary.LittleEndian, &version); err != nil {
+		return nil, fmt.Errorf("error reading book version: %w", err)
+	}
+	if version != bookVersion {
+		return nil, fmt.Errorf("unsupported book version: %d", version)
+	}
+	if err := binary.Read(br, binary.LittleEndian, &count); err != nil {
+		return nil, fmt.Errorf("error reading book size: %w", err)
+	}
+
+	bk := &Book{positions: make(map[chess.Hash][]shortMove)}
+	for i := uint32(0); i < count; i++ {
+		var e struct {
+			Key   uint64
+			Move  uint16
+			Count uint32
+		}
+		if err := binary.Read(br, binary.LittleEndian, &e); err != nil {
+			return nil, fmt.Errorf("error reading book entry %d: %w", i, err)
+		}
+		bk.positions[chess.Hash(e.Key)] = append(bk.positions[chess.Hash(e.Key)],
+			shortMove{count: int(e.Count), move: e.Move})
+	}
+	return bk, nil
+}
+
+// Save writes the book to a file in the compact binary format.
+func (bk *Book) Save(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("error creating book: %w", err)
+	}
+	if _, err := bk.WriteTo(f); err != nil {
+		f.Close()
+		return fmt.Errorf("error writing book: %w", err)
+	}
+	return f.Close()
+}
+
+// Load reads a book in the compact binary format from a file.
+func Load(path string) (*Book, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening book: %w", err)
+	}
+	defer f.Close()
+	return Read(f)
+}
+
+// Learn adjusts the book's weights from the result of a game. While the game
+// is in book, the winner's moves gain rate of their weight, and the loser's
+// moves lose it, so a move that keeps losing eventually drops out of the book.
+// Draws and unfinished games leave the book unchanged. Learn returns the
+// number of moves adjusted.
+func (bk *Book) Learn(g *notation.Game, rate float64) (int, error) {
+	white, ok := resultPoints(g.Result)
+	if !ok || white == 0.5 {
+		return 0, nil
+	}
+	b, err := g.Board()
+	if err != nil {
+		return 0, err
+	}
+
+	var n int
+	for _, san := range g.Moves {
+		moves, ok := bk.positions[b.PolyglotKey()]
+		if !ok {
+			break
+		}
+		m, err := notation.ParseSAN(b, san)
+		if err != nil {
+			return n, fmt.Errorf("ply %d: %w", b.Ply(), err)
+		}
+		pm := encodePolyglotMove(m)
+		idx := slices.IndexFunc(moves, func(sm shortMove) bool { return sm.move == pm })
+		if idx < 0 {
+			break
+		}
+		delta := max(1, int(float64(moves[idx].count)*rate))
+		if won := (white == 1) == (b.Turn() == chess.White); won {
+			moves[idx].count += delta
+		} else {
+			moves[idx].count = max(0, moves[idx].count-delta)
+		}
+		sortShortMoves(moves)
+		n += 1
+		b.MakeMove(m)
+	}
+	return n, nil
+}
+
+// LearnPGN adjusts the book's weights from every game in a PGN database, like
+// the ones written by the match runner. It returns the number of games that
+// changed the book.
+func (bk *Book) LearnPGN(r io.Reader, rate float64) (int, error) {
+	pr := notation.NewPGNReader(r)
+	var learned int
+	for game := 1; ; game++ {
+		g, err := pr.Next()
+		if errors.Is(err, io.EOF) {
+			return learned, nil
+		} else if err != nil {
+			return learned, err
+		}
+		n, err := bk.Learn(g, rate)
+		if err != nil {
+			return learned, fmt.Errorf("game %d: %w", game, err)
+		}
+		if n != 0 {
+			learned += 1
+		}
+	}
+}
+
+// LoadFile loads an opening book of any supported format. Files in the
+// compact binary format are recognized by their contents, PGN and text files
+// by their extension (.pgn and .txt), and everything else is assumed to be a
+// Polyglot book.
+func LoadFile(path string) (Source, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading book: %w", err)
+	}
+	if bytes.HasPrefix(data, bookMagic) {
+		return Read(bytes.NewReader(data))
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".pgn", ".txt":
+		bb := NewBuilder()
+		if err := bb.AddFile(path); err != nil {
+			return nil, err
+		}
+		return bb.Book(), nil
+	}
+	return ReadPolyglot(bytes.NewReader(data))
+}
+
+// moveStats are the statistics Builder keeps for each move.
+type moveStats struct {
+	games  int     // Number of games the move was played in.
+	scored int     // Number of those games with a known result.
+	points float64 // Points scored by the side playing the move.
+}
+
+// Builder builds a Book from PGN databases, or from the text format used
+// by the built-in book.
+type Builder struct {
+	// Filters applied when building the Book.
+	MaxPly   int     // Only include positions fewer than MaxPly plies deep (0 means no limit).
+	MinGames int     // Only include moves played in at least MinGames games.
+	MinScore float64 // Only include moves scoring at least MinScore [0..1] for the side playing them.
+
+	stats map[chess.Hash]map[uint16]*moveStats
+	plies map[chess.Hash]int // The shallowest ply each position was seen at, if known.
+}
+
+// NewBuilder creates an empty Builder.
+func NewBuilder() *Builder {
+	return &Builder{
+		stats: make(map[chess.Hash]map[uint16]*moveStats),
+		plies: make(map[chess.Hash]int),
+	}
+}
+
+// add records a move for a position.
+func (bb *Builder) add(key chess.Hash, m chess.Move, st moveStats) {
+	moves, ok := bb.stats[key]
+	if !ok {
+		moves = make(map[uint16]*moveStats)
+		bb.stats[key] = moves
+	}
+	pm := encodePolyglotMove(m)
+	if cur, ok := moves[pm]; ok {
+		cur.games += st.games
+		cur.scored += st.scored
+		cur.points += st.points
+	} else {
+		moves[pm] = &st
+	}
+}
+
+// resultPoints returns the points white scored for a PGN result.
+func resultPoints(result string) (float64, bool) {
+	switch result {
+	case "1-0":
+		return 1, true
+	case "0-1":
+		return 0, true
+	case "1/2-1/2":
+		return 0.5, true
+	}
+	return 0, false
+}
+
+// AddGame adds the moves of a game to the book. Games with an illegal move
+// aren't added at all.
+func (bb *Builder) AddGame(g *notation.Game) error {
+	b, err := g.Board()
+	if err != nil {
+		return err
+	}
+
+	// Replay the whole game first, so we don't add part of a bad game.
+	type played struct {
+		key  chess.Hash
+		move chess.Move
+		turn chess.Piece
+	}
+	var moves []played
+	for ply, san := range g.Moves {
+		m, err := notation.ParseSAN(b, san)
+		if err != nil {
+			return fmt.Errorf("move %d: %w", ply/2+1, err)
+		}
+		moves = append(moves, played{key: b.PolyglotKey(), move: m, turn: b.Turn()})
+		b.MakeMove(m)
+	}
+
+	white, scored := resultPoints(g.Result)
+	for ply, p := range moves {
+		if bb.MaxPly != 0 && ply >= bb.MaxPly {
+			break
+		}
+		if cur, ok := bb.plies[p.key]; !ok || ply < cur {
+			bb.plies[p.key] = ply
+		}
+		st := moveStats{games: 1}
+		if scored {
+			st.scored = 1
+			st.points = white
+			if p.turn == chess.Black {
+				st.points = 1 - white
+			}
+		}
+		bb.add(p.key, p.move, st)
+	}
+	return nil
+}
+
+// AddPGN adds all the games in a PGN database to the book. Games that can't be
+// replayed are skipped, and their count is returned.
+func (bb *Builder) AddPGN(r io.Reader) (skipped int, err error) {
+	pr := notation.NewPGNReader(r)
+	for {
+		g, err := pr.Next()
+		if errors.Is(err, io.EOF) {
+			return skipped, nil
+		} else if err != nil {
+			return skipped, err
+		}
+		if err := bb.AddGame(g); err != nil {
+			skipped += 1
+		}
+	}
+}
+
+// AddText adds positions in the text format used by the built-in book:
+//
+//	pos <first 4 fields of a FEN>
+//	<move in long algebraic notation> <count>
+//	...
+//
+// Positions that appear more than once have their counts summed.
+func (bb *Builder) AddText(r io.Reader) error {
+	b, havePos := chess.EmptyBoard(chess.WithPolyglotHashing()), false
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		str := strings.Trim(scanner.Text(), " \t")
+		if len(str) == 0 {
+			continue
+		}
+		fen, ok := strings.CutPrefix(str, "pos ")
+		if ok {
+			if err := b.SetFEN(fen + " 0 1"); err != nil {
+				return fmt.Errorf("line %d: %w", line, err)
+			}
+			havePos = true
+			continue
+		}
+		if !havePos {
+			return fmt.Errorf("line %d: move without a position", line)
+		}
+
+		fields := strings.Fields(str)
+		if len(fields) != 2 {
+			return fmt.Errorf("line %d: expected a move and count: %q", line, str)
+		}
+		m, err := b.ParseMove(fields[0])
+		if err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		c, err := strconv.Atoi(fields[1])
+		if err != nil || c < 0 {
+			return fmt.Errorf("line %d: invalid count: %q", line, fields[1])
+		}
+		bb.add(b.PolyglotKey(), m, moveStats{games: c})
+	}
+	return scanner.Err()
+}
+
+// AddFile adds a PGN database (.pgn) or text book to the book.
+func (bb *Builder) AddFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("error opening %q: %w", path, err)
+	}
+	defer f.Close()
+	if strings.ToLower(filepath.Ext(path)) == ".pgn" {
+		_, err = bb.AddPGN(f)
+	} else {
+		err = bb.AddText(f)
+	}
+	if err != nil {
+		return fmt.Errorf("error reading %q: %w", path, err)
+	}
+	return nil
+}
+
+// depthFilter returns the set of positions within MaxPly plies of the start
+// position. Positions from text books have no ply, so we find it by walking
+// the book's moves.
+func (bb *Builder) depthFilter() map[chess.Hash]struct{} {
+	keep := make(map[chess.Hash]struct{})
+	for key, ply := range bb.plies {
+		if ply < bb.MaxPly {
+			keep[key] = struct{}{}
+		}
+	}
+	best := make(map[chess.Hash]int)
+	b := chess.New(chess.WithPolyglotHashing())
+	var walk func(ply int)
+	walk = func(ply int) {
+		key := b.PolyglotKey()
+		moves, ok := bb.stats[key]
+		if !ok || ply >= bb.MaxPly {
+			return
+		}
+		if p, ok := best[key]; ok && p <= ply {
+			return
+		}
+		best[key] = ply
+		keep[key] = struct{}{}
+		for pm := range moves {
+			m, err := decodePolyglotMove(b, pm)
+			if err != nil {
+				continue
+			}
+			b.MakeMove(m)
+			walk(ply + 1)
+			b.UnmakeMove()
+		}
+	}
+	walk(0)
+	return keep
+}
+
+// Book returns a Book with the builder's filters applied.
+func (bb *Builder) Book() *Book {
+	var keep map[chess.Hash]struct{}
+	if bb.MaxPly != 0 {
+		keep = bb.depthFilter()
+	}
+
+	bk := &Book{positions: make(map[chess.Hash][]shortMove)}
+	for key, moves := range bb.stats {
+		if keep != nil {
+			if _, ok := keep[key]; !ok {
+				continue
+			}
+		}
+		for pm, st := range moves {
+			if st.games == 0 || st.games < bb.MinGames {
+				continue
+			}
+			if st.scored != 0 && st.points/float64(st.scored) < bb.MinScore {
+				continue
+			}
+			bk.positions[key] = append(bk.positions[key], shortMove{count: st.games, move: pm})
+		}
+		sortShortMoves(bk.positions[key])
+	}
+	return bk
+}
@@ -0,0 +1,252 @@
+package book
+
+import (
+	"bytes"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"chess"
+)
+
+// bookMoves returns the long algebraic strings of the book moves for a position.
+func bookMoves(t *testing.T, ob Source, moves ...string) map[string]int {
+	t.Helper()
+	b := chess.New()
+	if err := b.ApplyMoves(moves); err != nil {
+		t.Fatalf("error applying moves %v: %v", moves, err)
+	}
+	res := make(map[string]int)
+	for _, bm := range ob.Moves(b) {
+		res[bm.Move.UCIString()] = bm.Weight
+	}
+	return res
+}
+
+func TestBuiltinBook(t *testing.T) {
+	bk := Builtin()
+	if bk.Len() == 0 {
+		t.Fatalf("built-in book is empty")
+	}
+	if moves := bookMoves(t, bk); moves["e2e4"] != 243109 {
+		t.Errorf("start position moves = %v, expected e2e4 243109", moves)
+	}
+
+	// The book's FENs don't list en passant targets that can't be captured, but
+	// we should still find the position after a double pawn push.
+	if moves := bookMoves(t, bk, "d2d4"); len(moves) == 0 {
+		t.Errorf("no book moves after d2d4")
+	}
+
+	r := rand.New(rand.NewSource(1))
+	if _, ok := bk.Pick(chess.New(), r); !ok {
+		t.Errorf("Pick(New()) found no move")
+	}
+}
+
+func TestBookText(t *testing.T) {
+	tests := []struct {
+		desc  string
+		text  string
+		moves map[string]int
+		isErr bool
+	}{
+		{
+			"merges duplicates",
+			"pos rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq -\ne2e4 2\nd2d4 1\n" +
+				"pos rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq -\ne2e4 3\n",
+			map[string]int{"e2e4": 5, "d2d4": 1},
+			false,
+		},
+		{"illegal move", "pos rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq -\ne2e5 2\n", nil, true},
+		{"bad count", "pos rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq -\ne2e4 x\n", nil, true},
+		{"no position", "e2e4 2\n", nil, true},
+	}
+
+	for _, test := range tests {
+		bb := NewBuilder()
+		err := bb.AddText(strings.NewReader(test.text))
+		if (err != nil) != test.isErr {
+			t.Errorf("[%s] AddText() = %v, expected error = %t", test.desc, err, test.isErr)
+		}
+		if err != nil {
+			continue
+		}
+		if moves := bookMoves(t, bb.Book()); !reflect.DeepEqual(moves, test.moves) {
+			t.Errorf("[%s] moves = %v, expected %v", test.desc, moves, test.moves)
+		}
+	}
+}
+
+func TestBookPGNFilters(t *testing.T) {
+	const pgn = `[Result "1-0"]
+1. e4 e5 2. Nf3 1-0
+[Result "1-0"]
+1. e4 c5 2. Nf3 1-0
+[Result "0-1"]
+1. d4 d5 0-1
+[Result "*"]
+1. e4 e5 2. Qh5 *
+[Result "1-0"]
+1. e4 e5 2. Ke3 1-0
+`
+	tests := []struct {
+		desc               string
+		maxPly, minGames   int
+		minScore           float64
+		start, afterE4, e5 map[string]int
+	}{
+		{"no filters", 0, 0, 0,
+			map[string]int{"e2e4": 3, "d2d4": 1}, map[string]int{"e7e5": 2, "c7c5": 1}, map[string]int{"g1f3": 1, "d1h5": 1}},
+		{"depth", 2, 0, 0,
+			map[string]int{"e2e4": 3, "d2d4": 1}, map[string]int{"e7e5": 2, "c7c5": 1}, map[string]int{}},
+		{"games", 0, 2, 0,
+			map[string]int{"e2e4": 3}, map[string]int{"e7e5": 2}, map[string]int{}},
+		{"score", 0, 0, 0.5,
+			map[string]int{"e2e4": 3}, map[string]int{}, map[string]int{"g1f3": 1, "d1h5": 1}},
+	}
+
+	for _, test := range tests {
+		bb := NewBuilder()
+		bb.MaxPly, bb.MinGames, bb.MinScore = test.maxPly, test.minGames, test.minScore
+		skipped, err := bb.AddPGN(strings.NewReader(pgn))
+		if err != nil {
+			t.Fatalf("[%s] AddPGN() = %v", test.desc, err)
+		}
+		if skipped != 1 {
+			t.Errorf("[%s] AddPGN() skipped %d games, expected 1", test.desc, skipped)
+		}
+		bk := bb.Book()
+		if moves := bookMoves(t, bk); !reflect.DeepEqual(moves, test.start) {
+			t.Errorf("[%s] start moves = %v, expected %v", test.desc, moves, test.start)
+		}
+		if moves := bookMoves(t, bk, "e2e4"); !reflect.DeepEqual(moves, test.afterE4) {
+			t.Errorf("[%s] 1. e4 moves = %v, expected %v", test.desc, moves, test.afterE4)
+		}
+		if moves := bookMoves(t, bk, "e2e4", "e7e5"); !reflect.DeepEqual(moves, test.e5) {
+			t.Errorf("[%s] 1. e4 e5 moves = %v, expected %v", test.desc, moves, test.e5)
+		}
+	}
+}
+
+func TestBookRoundTrip(t *testing.T) {
+	bk := Builtin()
+	var buf bytes.Buffer
+	if _, err := bk.WriteTo(&buf); err != nil {
+		t.Fatalf("error writing book: %v", err)
+	}
+	read, err := Read(&buf)
+	if err != nil {
+		t.Fatalf("error reading book: %v", err)
+	}
+	if !reflect.DeepEqual(bk, read) {
+		t.Errorf("read book differs from written book")
+	}
+
+	if _, err := Read(strings.NewReader("not a book")); err == nil {
+		t.Errorf("Read(garbage) = nil, expected error")
+	}
+}
+
+func TestSelector(t *testing.T) {
+	var moves []Move
+	for _, bm := range []struct {
+		move   string
+		weight int
+	}{{"e2e4", 100}, {"d2d4", 60}, {"c2c4", 5}, {"g1f3", 0}} {
+		m, err := chess.New().ParseMove(bm.move)
+		if err != nil {
+			t.Fatalf("ParseMove(%q) = %v", bm.move, err)
+		}
+		moves = append(moves, Move{Move: m, Weight: bm.weight})
+	}
+	tests := []struct {
+		bs       Selector
+		expected map[string]bool
+	}{
+		{Selector{Mode: Best}, map[string]bool{"e2e4": true}},
+		{Selector{Mode: Weighted}, map[string]bool{"e2e4": true, "d2d4": true, "c2c4": true}},
+		{Selector{Mode: Weighted, MinWeight: 50}, map[string]bool{"e2e4": true, "d2d4": true}},
+		{Selector{Mode: Uniform}, map[string]bool{"e2e4": true, "d2d4": true, "c2c4": true}},
+		{Selector{Mode: Uniform, MinWeight: 61}, map[string]bool{"e2e4": true}},
+	}
+	for i, test := range tests {
+		r := rand.New(rand.NewSource(1))
+		seen := make(map[string]bool)
+		for j := 0; j < 200; j++ {
+			m, ok := test.bs.choose(moves, r)
+			if !ok {
+				t.Fatalf("[%d] choose() found no move", i)
+			}
+			seen[m.UCIString()] = true
+		}
+		if !reflect.DeepEqual(seen, test.expected) {
+			t.Errorf("[%d] %+v chose %v, expected %v", i, test.bs, seen, test.expected)
+		}
+	}
+
+	// MaxPly stops using the book.
+	bk := Builtin()
+	r := rand.New(rand.NewSource(1))
+	b := chess.New()
+	if _, ok := (Selector{MaxPly: 1}).Pick(bk, b, r); !ok {
+		t.Errorf("MaxPly 1 found no move at ply 0")
+	}
+	b.ApplyMoves([]string{"e2e4"})
+	if m, ok := (Selector{MaxPly: 1}).Pick(bk, b, r); ok {
+		t.Errorf("MaxPly 1 found %v at ply 1", m)
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	for _, mode := range []Mode{Weighted, Best, Uniform} {
+		if m, err := ParseMode(mode.String()); err != nil || m != mode {
+			t.Errorf("ParseMode(%q) = %v, %v, expected %v", mode.String(), m, err, mode)
+		}
+	}
+	if _, err := ParseMode("Random"); err == nil {
+		t.Errorf("ParseMode(\"Random\") expected error")
+	}
+}
+
+func TestBookLearn(t *testing.T) {
+	text := "pos rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq -\ne2e4 10\nd2d4 10\n" +
+		"pos rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq -\ne7e5 10\nc7c5 1\n"
+	pgn := `[Result "1-0"]
+1. e4 c5 2. Nf3 1-0
+
+[Result "1/2-1/2"]
+1. d4 d5 1/2-1/2
+
+[Result "0-1"]
+1. e4 c5 0-1
+
+[Result "0-1"]
+1. d4 Nf6 0-1
+`
+	bb := NewBuilder()
+	if err := bb.AddText(strings.NewReader(text)); err != nil {
+		t.Fatalf("AddText() = %v", err)
+	}
+	bk := bb.Book()
+	n, err := bk.LearnPGN(strings.NewReader(pgn), 0.5)
+	if err != nil {
+		t.Fatalf("LearnPGN() = %v", err)
+	}
+	if n != 3 {
+		t.Errorf("LearnPGN() = %d, expected 3", n)
+	}
+	if moves, expected := bookMoves(t, bk), map[string]int{"e2e4": 8, "d2d4": 5}; !reflect.DeepEqual(moves, expected) {
+		t.Errorf("start position moves = %v, expected %v", moves, expected)
+	}
+	if moves, expected := bookMoves(t, bk, "e2e4"), map[string]int{"e7e5": 10, "c7c5": 1}; !reflect.DeepEqual(moves, expected) {
+		t.Errorf("1. e4 moves = %v, expected %v", moves, expected)
+	}
+
+	// d2d4 lost more than e2e4, so it's no longer tied for best.
+	r := rand.New(rand.NewSource(1))
+	if m, _ := (Selector{Mode: Best}).Pick(bk, chess.New(), r); m.UCIString() != "e2e4" {
+		t.Errorf("best move = %v, expected e2e4", m)
+	}
+}
@@ -0,0 +1,189 @@
+package book
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"slices"
+	"sort"
+
+	"chess"
+)
+
+// Support for opening books in Polyglot's .bin format.
+//
+// http://hgm.nubati.net/book_format.html
+
+const polyglotEntrySize = 16
+
+// polyglotPromotions maps the promotion field of a Polyglot move to a Piece.
+var polyglotPromotions = []chess.Piece{chess.Empty, chess.Knight, chess.Bishop, chess.Rook, chess.Queen}
+
+// PolyglotEntry is a single entry in a Polyglot book.
+type PolyglotEntry struct {
+	Key    chess.Hash
+	Move   uint16
+	Weight uint16
+	Learn  uint32
+}
+
+// Polyglot is an opening book in Polyglot's .bin format.
+type Polyglot struct {
+	entries []PolyglotEntry // sorted by Key.
+}
+
+// decodePolyglotMove converts a Polyglot move into a legal Move on the board.
+func decodePolyglotMove(b *chess.Board, pm uint16) (chess.Move, error) {
+	to := chess.CoordFromXY(int(pm&7), int((pm>>3)&7))
+	from := chess.CoordFromXY(int((pm>>6)&7), int((pm>>9)&7))
+	promo := int((pm >> 12) & 7)
+	if promo >= len(polyglotPromotions) {
+		return chess.Move{}, fmt.Errorf("invalid polyglot promotion: %d", promo)
+	}
+
+	p := b.At(from)
+	if p == chess.Empty {
+		return chess.Move{}, fmt.Errorf("no piece at: %v", from)
+	}
+
+	// Polyglot encodes castling as the king capturing its own rook.
+	if p.IsKing() && from.X() == 4 && b.At(to) == chess.Rook|p.Color() {
+		if to.X() == 7 {
+			to = chess.CoordFromXY(6, to.Y())
+		} else if to.X() == 0 {
+			to = chess.CoordFromXY(2, to.Y())
+		}
+	}
+
+	str := from.String() + to.String()
+	if promo != 0 {
+		str += polyglotPromotions[promo].NoteString()
+	}
+	m, err := b.ParseMove(str)
+	if err != nil {
+		return chess.Move{}, fmt.Errorf("illegal polyglot move: %v", str)
+	}
+	return m, nil
+}
+
+// encodePolyglotMove converts a Move into Polyglot's move format.
+func encodePolyglotMove(m chess.Move) uint16 {
+	to := m.To()
+	if m.IsCastle() {
+		to = m.RookCoord()
+	}
+	pm := uint16(to.X()) | uint16(to.Y())<<3 | uint16(m.From().X())<<6 | uint16(m.From().Y())<<9
+	if m.IsPromotion() {
+		pm |= uint16(slices.Index(polyglotPromotions, m.Promotion().Colorless())) << 12
+	}
+	return pm
+}
+
+// ReadPolyglot reads a Polyglot book.
+func ReadPolyglot(r io.Reader) (*Polyglot, error) {
+	pb := &Polyglot{}
+	var buf [polyglotEntrySize]byte
+	br := bufio.NewReader(r)
+	for {
+		if _, err := io.ReadFull(br, buf[:]); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("error reading polyglot entry %d: %w", len(pb.entries), err)
+		}
+		pb.entries = append(pb.entries, PolyglotEntry{
+			Key:    chess.Hash(binary.BigEndian.Uint64(buf[0:8])),
+			Move:   binary.BigEndian.Uint16(buf[8:10]),
+			Weight: binary.BigEndian.Uint16(buf[10:12]),
+			Learn:  binary.BigEndian.Uint32(buf[12:16]),
+		})
+	}
+
+	// Books should already be sorted, but we rely on it for lookups.
+	sort.SliceStable(pb.entries, func(i, j int) bool {
+		return pb.entries[i].Key < pb.entries[j].Key
+	})
+	return pb, nil
+}
+
+// LoadPolyglot reads a Polyglot book from a file.
+func LoadPolyglot(path string) (*Polyglot, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening book: %w", err)
+	}
+	defer f.Close()
+	return ReadPolyglot(f)
+}
+
+// WriteTo writes the book in Polyglot format.
+func (pb *Polyglot) WriteTo(w io.Writer) (int64, error) {
+	bw := bufio.NewWriter(w)
+	var buf [polyglotEntrySize]byte
+	var n int64
+	for _, e := range pb.entries {
+		binary.BigEndian.PutUint64(buf[0:8], uint64(e.Key))
+		binary.BigEndian.PutUint16(buf[8:10], e.Move)
+		binary.BigEndian.PutUint16(buf[10:12], e.Weight)
+		binary.BigEndian.PutUint32(buf[12:16], e.Learn)
+		c, err := bw.Write(buf[:])
+		n += int64(c)
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, bw.Flush()
+}
+
+// Len returns the number of entries in the book.
+func (pb *Polyglot) Len() int {
+	return len(pb.entries)
+}
+
+// lookup returns the entries for a given key.
+func (pb *Polyglot) lookup(key chess.Hash) []PolyglotEntry {
+	i := sort.Search(len(pb.entries), func(i int) bool {
+		return pb.entries[i].Key >= key
+	})
+	j := i
+	for j < len(pb.entries) && pb.entries[j].Key == key {
+		j++
+	}
+	return pb.entries[i:j]
+}
+
+// Moves returns the legal book moves for a board. Entries that don't decode to
+// a legal move (eg, from a hash collision) are skipped.
+func (pb *Polyglot) Moves(b *chess.Board) []Move {
+	var moves []Move
+	for _, e := range pb.lookup(b.PolyglotKey()) {
+		m, err := decodePolyglotMove(b, e.Move)
+		if err != nil {
+			continue
+		}
+		moves = append(moves, Move{Move: m, Weight: int(e.Weight)})
+	}
+	return moves
+}
+
+// Pick chooses a book move, weighted by the entries' weights.
+func (pb *Polyglot) Pick(b *chess.Board, r *rand.Rand) (chess.Move, bool) {
+	return pickMove(pb.Moves(b), r)
+}
+
+// Export writes the built-in book to a file in Polyglot format.
+func Export(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("error creating book: %w", err)
+	}
+	if _, err := Builtin().Polyglot().WriteTo(f); err != nil {
+		f.Close()
+		return fmt.Errorf("error writing book: %w", err)
+	}
+	return f.Close()
+}
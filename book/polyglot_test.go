@@ -0,0 +1,85 @@
+package book
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	"chess"
+)
+
+func TestPolyglotMove(t *testing.T) {
+	tests := []struct {
+		fen  string
+		move string
+		pm   uint16
+	}{
+		{chess.StartingFEN, "e2e4", 0x031c},
+		{"r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1", "e1g1", 0x0107},
+		{"r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1", "e1c1", 0x0100},
+		{"r3k2r/8/8/8/8/8/8/R3K2R b KQkq - 0 1", "e8g8", 0x0f3f},
+		{"k7/4P3/8/8/8/8/8/K7 w - - 0 1", "e7e8n", 0x1d3c},
+	}
+
+	for i, test := range tests {
+		b, err := chess.FromFEN(test.fen)
+		if err != nil {
+			t.Fatalf("[%d] error creating board: %v", i, err)
+		}
+		m, err := decodePolyglotMove(b, test.pm)
+		if err != nil {
+			t.Fatalf("[%d] decodePolyglotMove(%04x) = %v", i, test.pm, err)
+		}
+		if s := m.UCIString(); s != test.move {
+			t.Errorf("[%d] decodePolyglotMove(%04x) = %v, expected %v", i, test.pm, s, test.move)
+		}
+		if pm := encodePolyglotMove(m); pm != test.pm {
+			t.Errorf("[%d] encodePolyglotMove(%v) = %04x, expected %04x", i, m, pm, test.pm)
+		}
+	}
+}
+
+func TestPolyglotRoundTrip(t *testing.T) {
+	pb := Builtin().Polyglot()
+	if pb.Len() == 0 {
+		t.Fatalf("built-in book is empty")
+	}
+
+	var buf bytes.Buffer
+	if _, err := pb.WriteTo(&buf); err != nil {
+		t.Fatalf("error writing book: %v", err)
+	}
+	if buf.Len() != pb.Len()*polyglotEntrySize {
+		t.Errorf("wrote %d bytes, expected %d", buf.Len(), pb.Len()*polyglotEntrySize)
+	}
+	read, err := ReadPolyglot(&buf)
+	if err != nil {
+		t.Fatalf("error reading book: %v", err)
+	}
+	if read.Len() != pb.Len() {
+		t.Fatalf("read %d entries, expected %d", read.Len(), pb.Len())
+	}
+
+	// The most popular opening move in the built-in book is e4.
+	moves := read.Moves(chess.New())
+	if len(moves) == 0 {
+		t.Fatalf("no book moves for the starting position")
+	}
+	if s := moves[0].Move.String(); s != "e2e4" {
+		t.Errorf("top book move = %v, expected e2e4", s)
+	}
+
+	// Every book move should be playable.
+	r := rand.New(rand.NewSource(1))
+	b := chess.New()
+	for i := 0; i < 10; i++ {
+		m, ok := read.Pick(b, r)
+		if !ok {
+			break
+		}
+		b.MakeMove(m)
+	}
+	if b.Ply() == 0 {
+		t.Errorf("no moves picked from the book")
+	}
+}
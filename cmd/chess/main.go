@@ -0,0 +1,106 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"runtime/pprof"
+	"runtime/trace"
+	"strings"
+
+	"chess/book"
+	"chess/search"
+	"chess/uci"
+)
+
+var (
+	cpuProfile   = flag.String("cpuprofile", "", "filename where we should write the cpu profile")
+	memProfile   = flag.String("memprofile", "", "filename where we should write the mem profile")
+	traceProfile = flag.String("traceprofile", "", "filename where we should write trace output")
+	seed         = flag.Int64("seed", 0, "seed for the engine's random choices, so games can be reproduced (0 seeds from the clock)")
+	exportBook   = flag.String("exportbook", "", "filename where we should write the built-in book in Polyglot format")
+	makeBook     = flag.String("makebook", "", "filename where we should write a book built from the -bookfrom files")
+	bookFrom     = flag.String("bookfrom", "", "comma separated list of PGN (.pgn) or text books to build a book from")
+	bookDepth    = flag.Int("bookdepth", 0, "maximum depth, in plies, of the positions in a built book (0 for no limit)")
+	bookMinGames = flag.Int("bookmingames", 1, "minimum number of games a move must be played in to be in a built book")
+	bookMinScore = flag.Float64("bookminscore", 0, "minimum score [0..1] a move must achieve to be in a built book")
+	learnBook    = flag.String("learnbook", "", "book file whose weights should be updated from the -learnfrom games")
+	learnFrom    = flag.String("learnfrom", "", "PGN file of games, eg from the runner, to learn book weights from")
+	learnRate    = flag.Float64("learnrate", 0.1, "fraction of its weight a book move gains for a win, or loses for a loss")
+)
+
+func main() {
+	flag.Parse()
+	if len(*exportBook) != 0 {
+		if err := book.Export(*exportBook); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if len(*makeBook) != 0 {
+		bb := book.NewBuilder()
+		bb.MaxPly, bb.MinGames, bb.MinScore = *bookDepth, *bookMinGames, *bookMinScore
+		for _, path := range strings.Split(*bookFrom, ",") {
+			if err := bb.AddFile(path); err != nil {
+				log.Fatal(err)
+			}
+		}
+		if err := bb.Book().Save(*makeBook); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if len(*learnBook) != 0 {
+		if err := learn(*learnBook, *learnFrom, *learnRate); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if len(*cpuProfile) != 0 {
+		f, err := os.Create(*cpuProfile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		pprof.StartCPUProfile(f)
+		defer pprof.StopCPUProfile()
+	}
+	if len(*memProfile) != 0 {
+		f, err := os.Create(*memProfile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer pprof.WriteHeapProfile(f)
+	}
+	if len(*traceProfile) != 0 {
+		f, err := os.Create(*traceProfile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		trace.Start(f)
+		defer trace.Stop()
+	}
+
+	u := uci.NewEngine(search.WithSeed(*seed))
+	if err := u.Run(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// learn updates the weights of a book file from the results of a PGN file.
+func learn(bookPath, pgnPath string, rate float64) error {
+	bk, err := book.Load(bookPath)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(pgnPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	n, err := bk.LearnPGN(f, rate)
+	if err != nil {
+		return err
+	}
+	log.Printf("learned from %d games", n)
+	return bk.Save(bookPath)
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"chess"
+)
+
+// mismatch is a position where our move generation disagrees with the
+// reference.
+type mismatch struct {
+	fen     string   // The position's FEN.
+	path    []string // The moves from the root position to it.
+	depth   int
+	missing []string // Moves only the reference generated.
+	extra   []string // Moves only we generated.
+
+	// If no moves are missing or extra, the move whose count differs, which
+	// didn't show a mismatch when divided further.
+	move string
+}
+
+// String describes the mismatch.
+func (m *mismatch) String() string {
+	var sb strings.Builder
+	path := strings.Join(m.path, " ")
+	if len(path) == 0 {
+		path = "(root)"
+	}
+	fmt.Fprintf(&sb, "FEN:   %v\n", m.fen)
+	fmt.Fprintf(&sb, "Moves: %v\n", path)
+	if len(m.missing) != 0 {
+		fmt.Fprintf(&sb, "Missing moves: %v\n", strings.Join(m.missing, " "))
+	}
+	if len(m.extra) != 0 {
+		fmt.Fprintf(&sb, "Extra moves: %v\n", strings.Join(m.extra, " "))
+	}
+	if len(m.move) != 0 {
+		fmt.Fprintf(&sb, "Count of %v differs at depth %d\n", m.move, m.depth)
+	}
+	return sb.String()
+}
+
+// writeDivide writes our counts and the reference's side by side, marking
+// those that differ.
+func writeDivide(w io.Writer, ours, theirs map[string]uint64) error {
+	var moves []string
+	for m := range ours {
+		moves = append(moves, m)
+	}
+	for m := range theirs {
+		if _, ok := ours[m]; !ok {
+			moves = append(moves, m)
+		}
+	}
+	sort.Strings(moves)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "Move\tOurs\tReference\t\t")
+	count := func(counts map[string]uint64, m string) string {
+		if v, ok := counts[m]; ok {
+			return fmt.Sprint(v)
+		}
+		return "-"
+	}
+	for _, m := range moves {
+		mark := ""
+		if ours[m] != theirs[m] {
+			mark = "*"
+		}
+		fmt.Fprintf(tw, "%v\t%v\t%v\t%v\t\n", m, count(ours, m), count(theirs, m), mark)
+	}
+	return tw.Flush()
+}
+
+// debug runs divide on a position, comparing the counts with a reference's,
+// and recursing into the first move whose count differs until it finds the
+// position where the moves generated differ. Each divide is written to w. It
+// returns nil if the counts all match.
+func debug(ctx context.Context, ref reference, fen string, depth int, w io.Writer) (*mismatch, error) {
+	b, err := chess.FromFEN(fen)
+	if err != nil {
+		return nil, err
+	}
+	var path []string
+	var last *mismatch
+	for ; depth > 0; depth-- {
+		ours := b.Divide(depth)
+		theirs, err := ref.divide(ctx, fen, path, depth)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(w, "%v, depth %d:\n", b.FENString(), depth)
+		if err := writeDivide(w, ours, theirs); err != nil {
+			return nil, err
+		}
+		fmt.Fprintln(w)
+
+		m := &mismatch{fen: b.FENString(), path: append([]string(nil), path...), depth: depth}
+		for move := range theirs {
+			if _, ok := ours[move]; !ok {
+				m.missing = append(m.missing, move)
+			}
+		}
+		var differ []string
+		for move, cnt := range ours {
+			if v, ok := theirs[move]; !ok {
+				m.extra = append(m.extra, move)
+			} else if v != cnt {
+				differ = append(differ, move)
+			}
+		}
+		if len(m.missing) != 0 || len(m.extra) != 0 {
+			sort.Strings(m.missing)
+			sort.Strings(m.extra)
+			return m, nil
+		}
+		if len(differ) == 0 {
+			// The counts of the move we recursed into differ, but those below
+			// it don't.
+			return last, nil
+		}
+		sort.Strings(differ)
+		m.move = differ[0]
+		last = m
+
+		mv, err := b.ParseMove(m.move)
+		if err != nil {
+			return nil, err
+		}
+		b.MakeMove(mv)
+		path = append(path, m.move)
+	}
+	return last, nil
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"chess"
+)
+
+// testRef is a reference that's our own move generation, with an extra move
+// in one position, and an extra count for one move in another.
+type testRef struct {
+	extraFEN, extraMove string
+	countFEN, countMove string
+}
+
+func (r testRef) divide(ctx context.Context, fen string, moves []string, depth int) (map[string]uint64, error) {
+	b, err := chess.FromFEN(fen)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.ApplyMoves(moves); err != nil {
+		return nil, err
+	}
+	return r.count(b, depth), nil
+}
+
+func (r testRef) count(b *chess.Board, depth int) map[string]uint64 {
+	counts := make(map[string]uint64)
+	fen := b.FENString()
+	if fen == r.extraFEN {
+		counts[r.extraMove] = 1
+	}
+	for _, m := range b.PossibleMoves(nil) {
+		var cnt uint64 = 1
+		if depth > 1 {
+			b.MakeMove(m)
+			cnt = 0
+			for _, v := range r.count(b, depth-1) {
+				cnt += v
+			}
+			b.UnmakeMove()
+		}
+		if fen == r.countFEN && m.UCIString() == r.countMove {
+			cnt++
+		}
+		counts[m.UCIString()] = cnt
+	}
+	return counts
+}
+
+func TestDebug(t *testing.T) {
+	fen := func(moves ...string) string {
+		f, err := positionFEN(chess.StartingFEN, moves)
+		if err != nil {
+			t.Fatalf("positionFEN(%v) = %v", moves, err)
+		}
+		return f
+	}
+	tests := []struct {
+		ref      testRef
+		depth    int
+		expected *mismatch
+	}{
+		{testRef{}, 3, nil},
+		{
+			testRef{extraFEN: fen("e2e4", "e7e5"), extraMove: "e1e3"}, 3,
+			&mismatch{fen: fen("e2e4", "e7e5"), path: []string{"e2e4", "e7e5"}, depth: 1, missing: []string{"e1e3"}},
+		},
+		{
+			testRef{extraFEN: fen("e2e4", "e7e5"), extraMove: "e1e3"}, 1, nil,
+		},
+		{
+			testRef{extraFEN: fen(), extraMove: "e2e5"}, 2,
+			&mismatch{fen: fen(), depth: 2, missing: []string{"e2e5"}},
+		},
+		{
+			// Counts that differ without the moves differing.
+			testRef{countFEN: fen("d2d4"), countMove: "g8f6"}, 3,
+			&mismatch{fen: fen("d2d4"), path: []string{"d2d4"}, depth: 2, move: "g8f6"},
+		},
+	}
+	for i, test := range tests {
+		m, err := debug(context.Background(), test.ref, chess.StartingFEN, test.depth, io.Discard)
+		if err != nil {
+			t.Fatalf("[%d] debug() = %v", i, err)
+		}
+		if !reflect.DeepEqual(m, test.expected) {
+			t.Errorf("[%d] debug() = %+v, expected %+v", i, m, test.expected)
+		}
+	}
+}
+
+func TestExpectedCounts(t *testing.T) {
+	// Record a reference's counts, and debug with them instead.
+	ref := testRef{extraFEN: "rnbqkbnr/pppp1ppp/8/4p3/4P3/8/PPPP1PPP/RNBQKBNR w KQkq e6 0 2", extraMove: "e1e3"}
+	var sb strings.Builder
+	m, err := debug(context.Background(), recorder{ref: ref, w: &sb}, chess.StartingFEN, 3, io.Discard)
+	if err != nil || m == nil {
+		t.Fatalf("debug() = %v, %v, expected a mismatch", m, err)
+	}
+	if n := strings.Count(sb.String(), "\n"); n != 3 {
+		t.Errorf("recorded %d lines, expected 3", n)
+	}
+	fr, err := readCounts(strings.NewReader(sb.String()))
+	if err != nil {
+		t.Fatalf("readCounts() = %v", err)
+	}
+	fm, err := debug(context.Background(), fr, chess.StartingFEN, 3, io.Discard)
+	if err != nil {
+		t.Fatalf("debug() = %v", err)
+	}
+	if !reflect.DeepEqual(fm, m) {
+		t.Errorf("debug() = %+v, expected %+v", fm, m)
+	}
+
+	// Positions that aren't in the file are an error.
+	if _, err := debug(context.Background(), fr, chess.StartingFEN, 4, io.Discard); err == nil {
+		t.Errorf("debug() = nil, expected an error for missing counts")
+	}
+	for _, bad := range []string{"fen\t1\n", "fen\tx\ta2a3:1\n", "fen\t1\ta2a3\n"} {
+		if _, err := readCounts(strings.NewReader(bad)); err == nil {
+			t.Errorf("readCounts(%q) = nil, expected an error", bad)
+		}
+	}
+}
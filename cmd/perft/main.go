@@ -0,0 +1,105 @@
+// Command perft debugs move generation, comparing perft counts with those of
+// a reference.
+//
+//	perft [flags] depth [fen]
+//
+// It divides the position (the starting position if no FEN is given),
+// counting the leaf nodes at depth below each legal move, and compares the
+// counts with a reference UCI engine's "go perft", or those in an
+// expected-count file. If a count differs, it divides that move's position,
+// and so on, until it finds the position where the moves generated differ,
+// printing its FEN and the moves that lead to it.
+//
+// Counts from an engine can be saved with -record, to be used with
+// -expected later.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+
+	"chess"
+)
+
+var (
+	engine   = flag.String("engine", "", "reference UCI engine supporting \"go perft\"")
+	expected = flag.String("expected", "", "file of expected counts to use as the reference")
+	record   = flag.String("record", "", "file the reference's counts are appended to, for use with -expected")
+	quiet    = flag.Bool("q", false, "only print the mismatch")
+)
+
+func main() {
+	flag.Parse()
+	if flag.NArg() < 1 || (len(*engine) == 0) == (len(*expected) == 0) {
+		log.Fatalf("usage: %v [flags] depth [fen]\none of -engine or -expected is needed", os.Args[0])
+	}
+	depth, err := strconv.Atoi(flag.Arg(0))
+	if err != nil || depth < 1 {
+		log.Fatalf("error: invalid depth %q", flag.Arg(0))
+	}
+	fen := chess.StartingFEN
+	if flag.NArg() > 1 {
+		fen = strings.Join(flag.Args()[1:], " ")
+	}
+	if err := run(fen, depth); err != nil {
+		log.Fatalf("error: %v", err)
+	}
+}
+
+// run debugs a position against the reference.
+func run(fen string, depth int) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var ref reference
+	if len(*engine) != 0 {
+		fields := strings.Fields(*engine)
+		er, err := startEngineRef(ctx, fields[0], fields[1:]...)
+		if err != nil {
+			return fmt.Errorf("error starting reference: %w", err)
+		}
+		defer er.Close()
+		ref = er
+	} else {
+		f, err := os.Open(*expected)
+		if err != nil {
+			return err
+		}
+		fr, err := readCounts(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("error reading %v: %w", *expected, err)
+		}
+		ref = fr
+	}
+	if len(*record) != 0 {
+		f, err := os.OpenFile(*record, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		ref = recorder{ref: ref, w: f}
+	}
+
+	var out io.Writer = os.Stdout
+	if *quiet {
+		out = io.Discard
+	}
+	m, err := debug(ctx, ref, fen, depth, out)
+	switch {
+	case err != nil:
+		return err
+	case m == nil:
+		fmt.Println("All counts match.")
+	default:
+		fmt.Printf("Mismatch:\n%v", m)
+	}
+	return nil
+}
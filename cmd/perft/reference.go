@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"chess"
+	"chess/uci"
+)
+
+// reference gives the expected perft counts of positions.
+type reference interface {
+	// divide returns the perft count at depth below each of the legal moves
+	// of the position reached by playing moves from fen.
+	divide(ctx context.Context, fen string, moves []string, depth int) (map[string]uint64, error)
+}
+
+// positionFEN returns the FEN of the position reached by playing moves from
+// fen.
+func positionFEN(fen string, moves []string) (string, error) {
+	b, err := chess.FromFEN(fen)
+	if err != nil {
+		return "", err
+	}
+	if err := b.ApplyMoves(moves); err != nil {
+		return "", err
+	}
+	return b.FENString(), nil
+}
+
+// engineRef gets counts from a UCI engine supporting "go perft".
+type engineRef struct {
+	*uci.Process
+}
+
+// startEngineRef starts an engine to use as a reference.
+func startEngineRef(ctx context.Context, name string, args ...string) (*engineRef, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Stderr = os.Stderr
+	p, err := uci.StartProcess(cmd)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if err := p.Handshake(ctx); err != nil {
+		p.Close()
+		return nil, err
+	}
+	return &engineRef{p}, nil
+}
+
+func (r *engineRef) divide(ctx context.Context, fen string, moves []string, depth int) (map[string]uint64, error) {
+	if err := r.Position(fen, moves); err != nil {
+		return nil, err
+	}
+	counts, _, err := r.Perft(ctx, depth)
+	return counts, err
+}
+
+// countKey identifies a position's counts at a depth.
+type countKey struct {
+	fen   string
+	depth int
+}
+
+// fileRef gets counts from an expected-count file, where each line is a
+// position's FEN, a depth, and each move's count, separated by tabs:
+//
+//	<fen>\t<depth>\t<move>:<count> <move>:<count> ...
+type fileRef map[countKey]map[string]uint64
+
+// readCounts reads an expected-count file.
+func readCounts(r io.Reader) (fileRef, error) {
+	ref := make(fileRef)
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		if len(strings.TrimSpace(line)) == 0 || line[0] == '#' {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("line %d: expected 3 fields, got %d", n, len(fields))
+		}
+		depth, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid depth: %q", n, fields[1])
+		}
+		counts := make(map[string]uint64)
+		for _, f := range strings.Fields(fields[2]) {
+			move, cnt, ok := strings.Cut(f, ":")
+			v, err := strconv.ParseUint(cnt, 10, 64)
+			if !ok || err != nil {
+				return nil, fmt.Errorf("line %d: invalid count: %q", n, f)
+			}
+			counts[move] = v
+		}
+		ref[countKey{fields[0], depth}] = counts
+	}
+	return ref, scanner.Err()
+}
+
+// writeCounts writes a line of an expected-count file.
+func writeCounts(w io.Writer, fen string, depth int, counts map[string]uint64) error {
+	moves := make([]string, 0, len(counts))
+	for m := range counts {
+		moves = append(moves, m)
+	}
+	sort.Strings(moves)
+	for i, m := range moves {
+		moves[i] = fmt.Sprintf("%s:%d", m, counts[m])
+	}
+	_, err := fmt.Fprintf(w, "%s\t%d\t%s\n", fen, depth, strings.Join(moves, " "))
+	return err
+}
+
+func (r fileRef) divide(ctx context.Context, fen string, moves []string, depth int) (map[string]uint64, error) {
+	pos, err := positionFEN(fen, moves)
+	if err != nil {
+		return nil, err
+	}
+	counts, ok := r[countKey{pos, depth}]
+	if !ok {
+		return nil, fmt.Errorf("no expected counts for %q at depth %d", pos, depth)
+	}
+	return counts, nil
+}
+
+// recorder writes the counts a reference gives to an expected-count file, so
+// they can be used again without it.
+type recorder struct {
+	ref reference
+	w   io.Writer
+}
+
+func (r recorder) divide(ctx context.Context, fen string, moves []string, depth int) (map[string]uint64, error) {
+	counts, err := r.ref.divide(ctx, fen, moves, depth)
+	if err != nil {
+		return nil, err
+	}
+	pos, err := positionFEN(fen, moves)
+	if err != nil {
+		return nil, err
+	}
+	return counts, writeCounts(r.w, pos, depth, counts)
+}
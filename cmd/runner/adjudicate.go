@@ -0,0 +1,93 @@
+package main
+
+import (
+	"chess/uci"
+)
+
+// mateScore is the centipawn score given to mate scores when adjudicating.
+const mateScore = 100000
+
+// adjudication are the rules for ending games early, on the engines' scores
+// or their length. Rules with a zero number of moves are disabled.
+type adjudication struct {
+	// A game is resigned when both engines agree one side's score is at
+	// least resignScore for resignMoves consecutive moves each.
+	resignScore int
+	resignMoves int
+
+	// A game is drawn when both engines' scores are within drawScore of 0 for
+	// drawMoves consecutive moves each, starting from move drawAfter.
+	drawScore int
+	drawMoves int
+	drawAfter int
+
+	// A game is drawn when it reaches maxMoves.
+	maxMoves int
+}
+
+// adjudicator applies the adjudication rules to a game.
+type adjudicator struct {
+	rules  adjudication
+	resign int // Plies one side has been winning, positive if it's white.
+	draw   int // Plies the score has been drawish.
+}
+
+// whiteScore returns an engine's score in centipawns from white's point of
+// view.
+func whiteScore(s uci.Score, side int) int {
+	cp := s.CP
+	if s.IsMate {
+		cp = mateScore
+		if s.Mate <= 0 {
+			cp = -mateScore
+		}
+	}
+	if side == 1 {
+		cp = -cp
+	}
+	return cp
+}
+
+// add records the info from a side's search at ply.
+func (a *adjudicator) add(ply, side int, info uci.Info) {
+	if !info.HasScore {
+		a.resign, a.draw = 0, 0
+		return
+	}
+	score := whiteScore(info.Score, side)
+
+	switch r := a.rules.resignScore; {
+	case score >= r && a.resign >= 0:
+		a.resign++
+	case score >= r:
+		a.resign = 1
+	case score <= -r && a.resign <= 0:
+		a.resign--
+	case score <= -r:
+		a.resign = -1
+	default:
+		a.resign = 0
+	}
+
+	if ply/2+1 >= a.rules.drawAfter && -a.rules.drawScore < score && score < a.rules.drawScore {
+		a.draw++
+	} else {
+		a.draw = 0
+	}
+}
+
+// result returns the result and reason if the game at ply should be
+// adjudicated, or an empty result if it should continue.
+func (a *adjudicator) result(ply int) (string, string) {
+	switch r := a.rules; {
+	case r.resignMoves > 0 && a.resign >= 2*r.resignMoves:
+		return "1-0", "resign adjudication"
+	case r.resignMoves > 0 && a.resign <= -2*r.resignMoves:
+		return "0-1", "resign adjudication"
+	case r.drawMoves > 0 && a.draw >= 2*r.drawMoves:
+		return "1/2-1/2", "draw adjudication"
+	case r.maxMoves > 0 && ply >= 2*r.maxMoves:
+		return "1/2-1/2", "move limit"
+	}
+	return "", ""
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"testing"
+
+	"chess/uci"
+)
+
+func TestAdjudicate(t *testing.T) {
+	cp := func(v int) uci.Info { return uci.Info{Score: uci.Score{CP: v}, HasScore: true} }
+	mate := func(v int) uci.Info { return uci.Info{Score: uci.Score{Mate: v, IsMate: true}, HasScore: true} }
+	rules := adjudication{resignScore: 500, resignMoves: 2, drawScore: 10, drawMoves: 2, drawAfter: 3, maxMoves: 10}
+	tests := []struct {
+		start  int        // The ply of the first score.
+		scores []uci.Info // From the point of view of the side to move.
+		result string
+		reason string
+	}{
+		{0, nil, "", ""},
+		// Both sides agree white is winning.
+		{0, []uci.Info{cp(600), cp(-600), cp(500), cp(-700)}, "1-0", "resign adjudication"},
+		{1, []uci.Info{cp(600), cp(-600), cp(500), cp(-700)}, "0-1", "resign adjudication"},
+		{0, []uci.Info{mate(3), mate(-2), mate(2), mate(-1)}, "1-0", "resign adjudication"},
+		{0, []uci.Info{cp(-600), mate(1), mate(-1), cp(900)}, "0-1", "resign adjudication"},
+		// Not for long enough, or they disagree, or a score's missing.
+		{0, []uci.Info{cp(600), cp(-600), cp(500)}, "", ""},
+		{0, []uci.Info{cp(600), cp(-600), cp(400), cp(-700), cp(600)}, "", ""},
+		{0, []uci.Info{cp(600), cp(600), cp(500), cp(-700)}, "", ""},
+		{0, []uci.Info{cp(600), cp(-600), {}, cp(-700)}, "", ""},
+		{0, []uci.Info{cp(600), cp(-600), cp(-600), cp(600), cp(-600), cp(600), cp(-600)}, "0-1", "resign adjudication"},
+		// Drawish scores, after move 3.
+		{4, []uci.Info{cp(0), cp(9), cp(-9), cp(5)}, "1/2-1/2", "draw adjudication"},
+		{2, []uci.Info{cp(0), cp(9), cp(-9), cp(5)}, "", ""},
+		{4, []uci.Info{cp(0), cp(10), cp(-9), cp(5)}, "", ""},
+		{4, []uci.Info{cp(0), cp(9), {}, cp(-9), cp(5)}, "", ""},
+		// The move limit.
+		{18, []uci.Info{cp(100), cp(100)}, "1/2-1/2", "move limit"},
+		{18, []uci.Info{cp(100)}, "", ""},
+	}
+	for i, test := range tests {
+		adj := adjudicator{rules: rules}
+		ply := test.start
+		for _, info := range test.scores {
+			adj.add(ply, ply%2, info)
+			ply++
+		}
+		if result, reason := adj.result(ply); result != test.result || reason != test.reason {
+			t.Errorf("[%d] result() = %q, %q, expected %q, %q", i, result, reason, test.result, test.reason)
+		}
+	}
+
+	// Disabled rules never adjudicate.
+	adj := adjudicator{}
+	for ply := 0; ply < 100; ply++ {
+		adj.add(ply, ply%2, cp(0))
+	}
+	if result, reason := adj.result(100); len(result) != 0 {
+		t.Errorf("result() = %q, %q, expected no result with no rules", result, reason)
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// engineConfig describes how to run an engine.
+type engineConfig struct {
+	Name    string            `json:"name"` // Overrides the name the engine reports.
+	Cmd     string            `json:"cmd"`
+	Args    []string          `json:"args"`
+	Dir     string            `json:"dir"`     // Working directory, if not the runner's.
+	Options map[string]string `json:"options"` // UCI options set when the engine starts.
+}
+
+// displayName returns the engine's name before it's been started.
+func (ec engineConfig) displayName() string {
+	if len(ec.Name) != 0 {
+		return ec.Name
+	}
+	return filepath.Base(ec.Cmd)
+}
+
+// loadEngines reads engine configurations from a JSON file holding an array
+// of engines, eg:
+//
+//	[
+//		{"name": "new", "cmd": "./chess", "options": {"TranspositionMB": "64"}},
+//		{"name": "old", "cmd": "chess", "args": ["-seed", "1"], "dir": "/tmp"}
+//	]
+func loadEngines(path string) ([]engineConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading engines: %w", err)
+	}
+	var engines []engineConfig
+	if err := json.Unmarshal(data, &engines); err != nil {
+		return nil, fmt.Errorf("error parsing %v: %w", path, err)
+	}
+	for i, ec := range engines {
+		if len(ec.Cmd) == 0 {
+			return nil, fmt.Errorf("%v: engine %d has no cmd", path, i)
+		}
+	}
+	return engines, nil
+}
+
+// pairing is two engines, by index, that play each other.
+type pairing struct {
+	a, b int
+}
+
+// job is a game in a tournament's schedule.
+type job struct {
+	index   int // Order of the game in the schedule.
+	pairing int // Index of the pairing.
+	game    int // Index of the game within the pairing.
+}
+
+// schedule returns the pairings for a tournament between n engines. A
+// "roundrobin" pairs every engine with every other, and a "gauntlet" pairs
+// the first engine with all the others.
+func schedule(kind string, n int) ([]pairing, error) {
+	var pairings []pairing
+	switch kind {
+	case "roundrobin":
+		for a := 0; a < n; a++ {
+			for b := a + 1; b < n; b++ {
+				pairings = append(pairings, pairing{a, b})
+			}
+		}
+	case "gauntlet":
+		for b := 1; b < n; b++ {
+			pairings = append(pairings, pairing{0, b})
+		}
+	default:
+		return nil, fmt.Errorf("unknown schedule: %q", kind)
+	}
+	return pairings, nil
+}
+
+// jobs returns the games to play, with games games for each pairing. The
+// pairings take turns playing pairs of games, so results for every pairing
+// come in as the tournament goes.
+func jobs(pairings []pairing, games int) []job {
+	var js []job
+	for g := 0; g < games; g += 2 {
+		for p := range pairings {
+			for i := g; i < min(g+2, games); i++ {
+				js = append(js, job{index: len(js), pairing: p, game: i})
+			}
+		}
+	}
+	return js
+}
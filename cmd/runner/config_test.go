@@ -0,0 +1,39 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSchedule(t *testing.T) {
+	tests := []struct {
+		kind     string
+		n        int
+		pairings []pairing
+		isErr    bool
+	}{
+		{"roundrobin", 2, []pairing{{0, 1}}, false},
+		{"roundrobin", 3, []pairing{{0, 1}, {0, 2}, {1, 2}}, false},
+		{"gauntlet", 4, []pairing{{0, 1}, {0, 2}, {0, 3}}, false},
+		{"swiss", 4, nil, true},
+	}
+	for i, test := range tests {
+		pairings, err := schedule(test.kind, test.n)
+		if (err != nil) != test.isErr {
+			t.Errorf("[%d] schedule(%q, %d) = %v, expected error = %t", i, test.kind, test.n, err, test.isErr)
+		}
+		if !reflect.DeepEqual(pairings, test.pairings) {
+			t.Errorf("[%d] schedule(%q, %d) = %v, expected %v", i, test.kind, test.n, pairings, test.pairings)
+		}
+	}
+}
+
+func TestJobs(t *testing.T) {
+	expected := []job{
+		{0, 0, 0}, {1, 0, 1}, {2, 1, 0}, {3, 1, 1},
+		{4, 0, 2}, {5, 1, 2},
+	}
+	if js := jobs([]pairing{{0, 1}, {0, 2}}, 3); !reflect.DeepEqual(js, expected) {
+		t.Errorf("jobs() = %v, expected %v", js, expected)
+	}
+}
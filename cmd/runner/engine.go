@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"chess/uci"
+)
+
+// handshakeTimeout is how long a prog has to answer uci and isready.
+const handshakeTimeout = 10 * time.Second
+
+// prog is an engine playing in the match.
+type prog struct {
+	*uci.Process
+	name string
+}
+
+// NewProg starts an engine, with the program's stderr written to stderr
+// (which may be nil).
+func NewProg(ec engineConfig, stderr io.Writer) (*prog, error) {
+	cmd := exec.Command(ec.Cmd, ec.Args...)
+	cmd.Dir = ec.Dir
+	cmd.Stderr = stderr
+	p, err := uci.StartProcess(cmd)
+	if err != nil {
+		return nil, err
+	}
+	return &prog{Process: p, name: filepath.Base(p.Path())}, nil
+}
+
+// init performs the UCI handshake, and sets the engine's options.
+func (p *prog) init(ctx context.Context, ec engineConfig) error {
+	ctx, cancel := context.WithTimeout(ctx, handshakeTimeout)
+	defer cancel()
+	if err := p.Handshake(ctx); err != nil {
+		return err
+	}
+	if len(p.Client.Name) != 0 {
+		p.name = p.Client.Name
+	}
+	if len(ec.Name) != 0 {
+		p.name = ec.Name
+	}
+	names := make([]string, 0, len(ec.Options))
+	for name := range ec.Options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := p.SetOption(name, ec.Options[name]); err != nil {
+			return err
+		}
+	}
+	return p.IsReady(ctx)
+}
+
+// newGame tells the prog a new game is starting.
+func (p *prog) newGame(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, handshakeTimeout)
+	defer cancel()
+	return p.NewGame(ctx)
+}
+
+// bestMove sends a position and a search, and returns the move the prog
+// chose, along with its last info. If the prog doesn't answer within
+// timeout, the returned error wraps context.DeadlineExceeded.
+func (p *prog) bestMove(ctx context.Context, fen string, moves []string, params uci.GoParams, timeout time.Duration) (uci.BestMove, uci.Info, error) {
+	if err := p.Position(fen, moves); err != nil {
+		return uci.BestMove{}, uci.Info{}, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	bm, info, err := p.Go(ctx, params, nil)
+	if err != nil {
+		return bm, info, fmt.Errorf("%v: %w", p.name, err)
+	}
+	return bm, info, nil
+}
@@ -1,102 +1,252 @@
+// Command runner plays matches and tournaments between UCI engines.
+//
+//	runner [flags] engine1 engine2 [engine...]
+//
+// Engines can also be configured, with arguments and options, in a JSON file
+// passed with -engines. With more than two engines, the runner plays a round
+// robin or gauntlet, and finishes with a ranking and crosstable.
+//
+// The engines alternate colors, playing each opening twice, and the games are
+// written to a PGN file. Games can be played in parallel, each worker running
+// its own copies of the engines, which are restarted if they crash or hang.
+// Long games can be adjudicated on the scores the engines report, or drawn
+// after a number of moves.
 package main
 
 import (
-	"bufio"
+	"context"
 	"flag"
 	"fmt"
 	"io"
 	"log"
 	"os"
-	"os/exec"
-	"strings"
+	"os/signal"
+	"sync"
+	"time"
+
+	"chess/notation"
 )
 
-type prog struct {
-	path    string
-	prog    *exec.Cmd
-	stdin   io.WriteCloser
-	stdout  io.ReadCloser
-	scanner *bufio.Scanner
-}
+var (
+	enginesFile  = flag.String("engines", "", "JSON file of engines to play, in addition to those on the command line")
+	scheduleKind = flag.String("schedule", "roundrobin", "how engines are paired: roundrobin, or gauntlet (the first engine against the rest)")
+	games        = flag.Int("games", 2, "number of games each pair of engines plays")
+	concurrency  = flag.Int("concurrency", 1, "number of games to play in parallel")
+	openingsFile = flag.String("openings", "", "EPD or PGN (.pgn) file of openings to start games from")
+	pgnFile      = flag.String("pgn", "games.pgn", "filename where the games are written in PGN")
+	logDir       = flag.String("logs", "", "directory where the engines' stderr is written")
+	baseTime     = flag.Duration("time", 10*time.Second, "time each side has for a game")
+	incTime      = flag.Duration("inc", 100*time.Millisecond, "time added to a side's clock after each move")
+	timeMargin   = flag.Duration("margin", 100*time.Millisecond, "how far past its clock an engine can go before it forfeits")
+	resignScore  = flag.Int("resignscore", 1000, "score, in centipawns, at which a game is resigned")
+	resignMoves  = flag.Int("resignmoves", 0, "consecutive moves each engine must agree on a resign score to resign a game, 0 to never resign")
+	drawScore    = flag.Int("drawscore", 10, "score, in centipawns, within which a game is drawn")
+	drawMoves    = flag.Int("drawmoves", 0, "consecutive moves each engine must agree on a draw score to draw a game, 0 to never draw")
+	drawAfter    = flag.Int("drawafter", 40, "move from which games can be drawn on their score")
+	maxMoves     = flag.Int("maxmoves", 0, "number of moves after which a game is drawn, 0 for no limit")
+	event        = flag.String("event", "Match", "event name for the PGN")
+	useSPRT      = flag.Bool("sprt", false, "run a SPRT, stopping the match when a hypothesis is accepted")
+	elo0         = flag.Float64("elo0", 0, "Elo difference of the SPRT's null hypothesis")
+	elo1         = flag.Float64("elo1", 5, "Elo difference of the SPRT's alternative hypothesis")
+	alpha        = flag.Float64("alpha", 0.05, "SPRT false positive rate")
+	beta         = flag.Float64("beta", 0.05, "SPRT false negative rate")
+)
 
-// NewProg makes a new prog interface.
-//
-// Note that the program has already been started.
-func NewProg(name string) (*prog, error) {
-	path, err := exec.LookPath(name)
-	if err != nil {
-		return nil, fmt.Errorf("error finding executable: %w", err)
-	}
-	cmd := exec.Command(path)
-	stdin, err := cmd.StdinPipe()
-	if err != nil {
-		return nil, fmt.Errorf("error attaching to stdin: %w", err)
-	}
-	stdout, err := cmd.StdoutPipe()
-	if err != nil {
-		return nil, fmt.Errorf("error attaching to stout: %w", err)
+// report prints the match's statistics.
+func report(names [2]string, s *stats, t *sprt) {
+	elo, margin := s.elo()
+	fmt.Printf("Score of %v vs %v: %d - %d - %d [%.3f] %d\n",
+		names[0], names[1], s.wins, s.losses, s.draws, s.score(), s.games())
+	fmt.Printf("Elo difference: %.1f +/- %.1f, LOS: %.1f%%\n", elo, margin, 100*s.los())
+	fmt.Printf("Ptnml(0-2): %v\n", s.penta)
+	if t != nil {
+		fmt.Println(t.report(s))
 	}
-	scanner := bufio.NewScanner(stdout)
-	go cmd.Run()
-	return &prog{path: path, prog: cmd, stdin: stdin, stdout: stdout, scanner: scanner}, nil
 }
 
-// writeln writes to a prog.
-//
-// It doesn't wait for a response. If cmd doesn't contain a newline, one is added.
-func (p *prog) writeln(cmd string) error {
-	toWrite := cmd
-	if !strings.HasSuffix(cmd, "\n") {
-		toWrite += "\n"
-	}
-	n, err := p.stdin.Write([]byte(toWrite))
-	if err != nil {
-		return fmt.Errorf("error writing %q command: %w", strings.Trim(cmd, " \t\n\r"), err)
+// gameResult is a finished game, or the error that stopped it.
+type gameResult struct {
+	job  job
+	game *notation.Game
+	err  error
+}
+
+func run(ctx context.Context, t *tournament, games int, out io.Writer, test *sprt) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var workers []*worker
+	for i := 0; i < max(*concurrency, 1); i++ {
+		workers = append(workers, t.newWorker(i))
 	}
-	if n != len(toWrite) {
-		return fmt.Errorf("didn't complete the write: %d != %d", n, len(toWrite))
+	defer func() {
+		// Hung engines take a while to kill, so shut the workers down together.
+		var wg sync.WaitGroup
+		for _, w := range workers {
+			wg.Add(1)
+			go func(w *worker) {
+				defer wg.Done()
+				w.Close()
+			}(w)
+		}
+		wg.Wait()
+	}()
+
+	// Hand out the games, and play them.
+	queue := make(chan job)
+	go func() {
+		defer close(queue)
+		for _, j := range jobs(t.pairings, games) {
+			select {
+			case queue <- j:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	results := make(chan gameResult)
+	var wg sync.WaitGroup
+	for _, w := range workers {
+		wg.Add(1)
+		go func(w *worker) {
+			defer wg.Done()
+			for j := range queue {
+				g, err := w.play(ctx, j)
+				results <- gameResult{job: j, game: g, err: err}
+			}
+		}(w)
 	}
-	return nil
-}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
 
-// RunCommand runs a command to a program, waiting for an output.
-func (p *prog) RunCommand(cmd string, output string) error {
-	if err := p.writeln(cmd); err != nil {
-		return err
+	// Collect the results as they finish.
+	names := make([]string, len(t.engines))
+	for i, ec := range t.engines {
+		names[i] = ec.displayName()
 	}
+	pairingStats := make([]stats, len(t.pairings))
+	var firstErr error
+	for r := range results {
+		if r.err != nil {
+			if ctx.Err() == nil {
+				firstErr = fmt.Errorf("game %d: %w", r.job.index+1, r.err)
+				cancel()
+			}
+			continue
+		}
+		g := r.game
+		if _, err := g.WriteTo(out); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("error writing game %d: %w", r.job.index+1, err)
+			cancel()
+		}
+
+		// Score the game for the pairing's first engine, who plays white in
+		// the even games.
+		pr, aWhite := t.pairings[r.job.pairing], r.job.game%2 == 0
+		if aWhite {
+			names[pr.a], names[pr.b] = g.Tags["White"], g.Tags["Black"]
+		} else {
+			names[pr.a], names[pr.b] = g.Tags["Black"], g.Tags["White"]
+		}
+		score := 0.5
+		switch {
+		case g.Result == "1-0" && aWhite, g.Result == "0-1" && !aWhite:
+			score = 1
+		case g.Result == "1-0", g.Result == "0-1":
+			score = 0
+		}
+		s := &pairingStats[r.job.pairing]
+		s.add(r.job.game/2, score)
+		fmt.Printf("Game %d: %v vs %v: %v {%v}\n", r.job.index+1, g.Tags["White"], g.Tags["Black"], g.Result, g.Tags["Termination"])
+		report([2]string{names[pr.a], names[pr.b]}, s, test)
 
-	for p.scanner.Scan() {
-		str := strings.Trim(p.scanner.Text(), " \t\r\n")
-		if strings.Contains(str, output) {
-			return nil
+		if test != nil && ctx.Err() == nil {
+			if h := test.result(s); len(h) != 0 {
+				fmt.Printf("SPRT: %v accepted\n", h)
+				cancel()
+			}
 		}
 	}
-	return nil
-}
 
-func run(p1, p2 *prog) error {
-	for _, p := range []*prog{p1, p2} {
-		if err := p.RunCommand("uci", "uciok"); err != nil {
-			return err
+	if len(t.pairings) > 1 {
+		fmt.Println()
+		if err := writeTables(os.Stdout, names, t.pairings, pairingStats); err != nil && firstErr == nil {
+			firstErr = err
 		}
 	}
-	return nil
+	return firstErr
 }
 
 func main() {
 	flag.Parse()
-	if flag.NArg() != 2 {
-		log.Fatalf("usage: %v [flags] p1 p2", os.Args[0])
+
+	var engines []engineConfig
+	if len(*enginesFile) != 0 {
+		var err error
+		if engines, err = loadEngines(*enginesFile); err != nil {
+			log.Fatalf("error: %v", err)
+		}
+	}
+	for _, cmd := range flag.Args() {
+		engines = append(engines, engineConfig{Cmd: cmd})
+	}
+	if len(engines) < 2 {
+		log.Fatalf("usage: %v [flags] [engine...]\nat least 2 engines are needed, from -engines or the command line", os.Args[0])
 	}
-	p1, err := NewProg(flag.Arg(0))
+	pairings, err := schedule(*scheduleKind, len(engines))
 	if err != nil {
 		log.Fatalf("error: %v", err)
 	}
-	p2, err := NewProg(flag.Arg(1))
+
+	openings := []opening{{}}
+	if len(*openingsFile) != 0 {
+		if openings, err = loadOpenings(*openingsFile); err != nil {
+			log.Fatalf("error: %v", err)
+		}
+	}
+	if len(*logDir) != 0 {
+		if err := os.MkdirAll(*logDir, 0777); err != nil {
+			log.Fatalf("error: %v", err)
+		}
+	}
+
+	t := &tournament{
+		engines:  engines,
+		pairings: pairings,
+		openings: openings,
+		tc:       timeControl{base: *baseTime, inc: *incTime, margin: *timeMargin},
+		adj: adjudication{
+			resignScore: *resignScore, resignMoves: *resignMoves,
+			drawScore: *drawScore, drawMoves: *drawMoves, drawAfter: *drawAfter,
+			maxMoves: *maxMoves,
+		},
+		event:  *event,
+		logDir: *logDir,
+	}
+	var test *sprt
+	if *useSPRT {
+		if len(pairings) != 1 {
+			log.Fatalf("error: a SPRT needs a match between 2 engines")
+		}
+		test = &sprt{elo0: *elo0, elo1: *elo1, alpha: *alpha, beta: *beta}
+	}
+
+	out, err := os.Create(*pgnFile)
 	if err != nil {
 		log.Fatalf("error: %v", err)
 	}
-	if err := run(p1, p2); err != nil {
+
+	// Stop cleanly on an interrupt, finishing the PGN and shutting down the
+	// engines.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	err = run(ctx, t, *games, out, test)
+	stop()
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
 		log.Fatalf("error running: %v", err)
 	}
 }
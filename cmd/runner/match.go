@@ -0,0 +1,231 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"chess"
+	"chess/notation"
+	"chess/uci"
+)
+
+// timeControl is the time each side has for a game.
+type timeControl struct {
+	base, inc time.Duration
+	margin    time.Duration // How far past its clock a side can go before forfeiting.
+}
+
+// String returns the time control as it's written in PGN, eg "60+0.5".
+func (tc timeControl) String() string {
+	return fmt.Sprintf("%s+%s",
+		strconv.FormatFloat(tc.base.Seconds(), 'f', -1, 64),
+		strconv.FormatFloat(tc.inc.Seconds(), 'f', -1, 64))
+}
+
+// tournament plays games between engines.
+type tournament struct {
+	engines  []engineConfig
+	pairings []pairing
+	openings []opening
+	tc       timeControl
+	adj      adjudication
+	event    string
+	logDir   string // Directory the engines' stderr is written to, if set.
+}
+
+// worker plays a tournament's games with its own engine processes, which are
+// started as they're needed.
+type worker struct {
+	id      int
+	t       *tournament
+	engines map[int]*prog
+	logs    map[int]io.WriteCloser
+}
+
+// newWorker creates a worker.
+func (t *tournament) newWorker(id int) *worker {
+	return &worker{id: id, t: t, engines: make(map[int]*prog), logs: make(map[int]io.WriteCloser)}
+}
+
+// start (re)starts one of the tournament's engines.
+func (w *worker) start(ctx context.Context, i int) error {
+	if p, ok := w.engines[i]; ok {
+		p.Close()
+		delete(w.engines, i)
+	}
+	ec := w.t.engines[i]
+	var stderr io.Writer
+	if len(w.t.logDir) != 0 {
+		if _, ok := w.logs[i]; !ok {
+			name := fmt.Sprintf("%d-%d-%s.log", w.id, i, filepath.Base(ec.Cmd))
+			f, err := os.OpenFile(filepath.Join(w.t.logDir, name), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+			if err != nil {
+				return fmt.Errorf("error opening log: %w", err)
+			}
+			w.logs[i] = f
+		}
+		stderr = w.logs[i]
+	}
+	p, err := NewProg(ec, stderr)
+	if err != nil {
+		return err
+	}
+	if err := p.init(ctx, ec); err != nil {
+		p.Close()
+		return fmt.Errorf("error starting %v: %w", ec.displayName(), err)
+	}
+	w.engines[i] = p
+	return nil
+}
+
+// newGame gets engines ready for a game, starting them if they haven't been,
+// and restarting any that have crashed or don't respond.
+func (w *worker) newGame(ctx context.Context, engines ...int) error {
+	for _, i := range engines {
+		if p, ok := w.engines[i]; ok && !p.Exited() && p.newGame(ctx) == nil {
+			continue
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err := w.start(ctx, i); err != nil {
+			return err
+		}
+		if err := w.engines[i].newGame(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close shuts down the worker's engines, and closes their logs.
+func (w *worker) Close() {
+	for _, p := range w.engines {
+		p.Close()
+	}
+	for _, l := range w.logs {
+		l.Close()
+	}
+}
+
+// loss returns the PGN result of the side to move on b losing.
+func loss(b *chess.Board) string {
+	if b.Ply()%2 == 0 {
+		return "0-1"
+	}
+	return "1-0"
+}
+
+// play plays one of the tournament's games. Each opening is played twice, with
+// the engines swapping colors.
+//
+// Games an engine loses by crashing, hanging, or making an illegal move are
+// still returned, and the engine is restarted before its next game. An error
+// means the game couldn't be played.
+func (w *worker) play(ctx context.Context, j job) (*notation.Game, error) {
+	t := w.t
+	pr := t.pairings[j.pairing]
+	white, black := pr.a, pr.b
+	if j.game%2 == 1 {
+		white, black = black, white
+	}
+	o := t.openings[(j.game/2)%len(t.openings)]
+
+	b, err := o.board()
+	if err != nil {
+		return nil, err
+	}
+	start, err := opening{fen: o.fen}.board()
+	if err != nil {
+		return nil, err
+	}
+	if err := w.newGame(ctx, white, black); err != nil {
+		return nil, err
+	}
+	players := [2]*prog{w.engines[white], w.engines[black]}
+
+	g := &notation.Game{
+		Tags: map[string]string{
+			"Event":       t.event,
+			"Date":        time.Now().Format("2006.01.02"),
+			"Round":       strconv.Itoa(j.index + 1),
+			"White":       players[0].name,
+			"Black":       players[1].name,
+			"TimeControl": t.tc.String(),
+		},
+		Result: "*",
+	}
+	if len(o.fen) != 0 {
+		g.Tags["FEN"], g.Tags["SetUp"] = o.fen, "1"
+	}
+
+	// Record the opening's moves.
+	moves := append([]string(nil), o.moves...)
+	for _, ms := range o.moves {
+		mv, err := start.ParseMove(ms)
+		if err != nil {
+			return nil, err
+		}
+		g.Moves = append(g.Moves, notation.SAN(start, mv))
+		start.MakeMove(mv)
+	}
+
+	clocks := [2]time.Duration{t.tc.base, t.tc.base}
+	adj := adjudicator{rules: t.adj}
+	for {
+		if result, reason := b.Outcome(); result != chess.InProgress {
+			g.Result, g.Tags["Termination"] = result.String(), reason
+			return g, nil
+		}
+		if result, reason := adj.result(b.Ply()); len(result) != 0 {
+			g.Result, g.Tags["Termination"] = result, reason
+			return g, nil
+		}
+
+		side := b.Ply() % 2
+		p := players[side]
+		params := uci.GoParams{WTime: clocks[0], BTime: clocks[1], WInc: t.tc.inc, BInc: t.tc.inc}
+
+		startTime := time.Now()
+		bm, info, err := p.bestMove(ctx, o.fen, moves, params, clocks[side]+t.tc.margin)
+		switch {
+		case ctx.Err() != nil:
+			return nil, ctx.Err()
+		case errors.Is(err, context.DeadlineExceeded):
+			// The engine might be hung, so restart it.
+			p.Close()
+			g.Result, g.Tags["Termination"] = loss(b), "time forfeit"
+			return g, nil
+		case errors.Is(err, uci.ErrClosed):
+			p.Close()
+			g.Result, g.Tags["Termination"] = loss(b), "engine crashed"
+			return g, nil
+		case err != nil:
+			p.Close()
+			g.Result, g.Tags["Termination"] = loss(b), fmt.Sprintf("engine error: %v", err)
+			return g, nil
+		}
+		if clocks[side] -= time.Since(startTime); clocks[side] < -t.tc.margin {
+			g.Result, g.Tags["Termination"] = loss(b), "time forfeit"
+			return g, nil
+		}
+		clocks[side] = max(clocks[side], 0) + t.tc.inc
+
+		// Validate the move (the same way ApplyStringMove does), and play it.
+		mv, err := b.ParseMove(bm.Move)
+		if err != nil {
+			g.Result, g.Tags["Termination"] = loss(b), fmt.Sprintf("illegal move %v", bm.Move)
+			return g, nil
+		}
+		adj.add(b.Ply(), side, info)
+		g.Moves = append(g.Moves, notation.SAN(b, mv))
+		b.MakeMove(mv)
+		moves = append(moves, bm.Move)
+	}
+}
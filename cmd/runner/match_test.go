@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"chess/uci/ucitest"
+)
+
+func TestMain(m *testing.M) {
+	ucitest.Main()
+	os.Exit(m.Run())
+}
+
+// fakeEngine returns the config to run e as a subprocess of the test.
+func fakeEngine(t *testing.T, e *ucitest.Engine) engineConfig {
+	t.Helper()
+	path, args, err := e.Command()
+	if err != nil {
+		t.Fatalf("Command() = %v", err)
+	}
+	return engineConfig{Name: e.Name, Cmd: path, Args: args}
+}
+
+// foolsMate is the fastest checkmate, by black.
+var foolsMate = map[int]string{0: "f2f3", 1: "e7e5", 2: "g2g4", 3: "d8h4"}
+
+func TestPlay(t *testing.T) {
+	tc := timeControl{base: 10 * time.Second, margin: 100 * time.Millisecond}
+	fast := timeControl{base: 200 * time.Millisecond, margin: 50 * time.Millisecond}
+	tests := []struct {
+		white, black ucitest.Engine
+		tc           timeControl
+		result       string
+		termination  string
+		moves        []string
+	}{
+		{
+			ucitest.Engine{Moves: foolsMate}, ucitest.Engine{Moves: foolsMate}, tc,
+			"0-1", "checkmate", []string{"f3", "e5", "g4", "Qh4#"},
+		},
+		{
+			ucitest.Engine{Moves: foolsMate, Faults: map[int]ucitest.Fault{0: ucitest.BadInfo}},
+			ucitest.Engine{Moves: foolsMate, Faults: map[int]ucitest.Fault{1: ucitest.BadInfo, 3: ucitest.BadInfo}}, tc,
+			"0-1", "checkmate", []string{"f3", "e5", "g4", "Qh4#"},
+		},
+		{
+			ucitest.Engine{Moves: foolsMate}, ucitest.Engine{Moves: foolsMate, Faults: map[int]ucitest.Fault{1: ucitest.Illegal}}, tc,
+			"1-0", "illegal move " + ucitest.IllegalMove, []string{"f3"},
+		},
+		{
+			ucitest.Engine{Moves: foolsMate, Faults: map[int]ucitest.Fault{2: ucitest.Crash}}, ucitest.Engine{Moves: foolsMate}, tc,
+			"0-1", "engine crashed", []string{"f3", "e5"},
+		},
+		{
+			ucitest.Engine{Moves: foolsMate}, ucitest.Engine{Moves: foolsMate, Faults: map[int]ucitest.Fault{3: ucitest.Hang}}, fast,
+			"1-0", "time forfeit", []string{"f3", "e5", "g4"},
+		},
+		{
+			ucitest.Engine{Moves: foolsMate, Delay: 150 * time.Millisecond}, ucitest.Engine{Moves: foolsMate}, fast,
+			"0-1", "time forfeit", []string{"f3", "e5"},
+		},
+	}
+	for i, test := range tests {
+		tr := &tournament{
+			engines:  []engineConfig{fakeEngine(t, &test.white), fakeEngine(t, &test.black)},
+			pairings: []pairing{{0, 1}},
+			openings: []opening{{}},
+			tc:       test.tc,
+		}
+		w := tr.newWorker(0)
+		g, err := w.play(context.Background(), job{})
+		w.Close()
+		if err != nil {
+			t.Errorf("[%d] play() = %v", i, err)
+			continue
+		}
+		if g.Result != test.result || g.Tags["Termination"] != test.termination {
+			t.Errorf("[%d] play() = %v {%v}, expected %v {%v}", i, g.Result, g.Tags["Termination"], test.result, test.termination)
+		}
+		if !reflect.DeepEqual(g.Moves, test.moves) {
+			t.Errorf("[%d] play() moves = %v, expected %v", i, g.Moves, test.moves)
+		}
+	}
+}
+
+func TestPlayRestart(t *testing.T) {
+	// The first engine crashes whenever it's white, so it needs restarting
+	// for the second game, where it's black.
+	crashy := &ucitest.Engine{Name: "crashy", Moves: foolsMate, Faults: map[int]ucitest.Fault{0: ucitest.Crash}}
+	other := &ucitest.Engine{Name: "other", Moves: foolsMate}
+	tr := &tournament{
+		engines:  []engineConfig{fakeEngine(t, crashy), fakeEngine(t, other)},
+		pairings: []pairing{{0, 1}},
+		openings: []opening{{}},
+		tc:       timeControl{base: 10 * time.Second, margin: 100 * time.Millisecond},
+	}
+	w := tr.newWorker(0)
+	defer w.Close()
+
+	tests := []struct {
+		game         int
+		white, black string
+		result       string
+		termination  string
+	}{
+		{0, "crashy", "other", "0-1", "engine crashed"},
+		{1, "other", "crashy", "0-1", "checkmate"},
+		{2, "crashy", "other", "0-1", "engine crashed"},
+	}
+	for i, test := range tests {
+		g, err := w.play(context.Background(), job{index: test.game, game: test.game})
+		if err != nil {
+			t.Fatalf("[%d] play() = %v", i, err)
+		}
+		if g.Tags["White"] != test.white || g.Tags["Black"] != test.black {
+			t.Errorf("[%d] play() = %v vs %v, expected %v vs %v", i, g.Tags["White"], g.Tags["Black"], test.white, test.black)
+		}
+		if g.Result != test.result || g.Tags["Termination"] != test.termination {
+			t.Errorf("[%d] play() = %v {%v}, expected %v {%v}", i, g.Result, g.Tags["Termination"], test.result, test.termination)
+		}
+	}
+}
+
+func TestPlayCancel(t *testing.T) {
+	hung := &ucitest.Engine{Faults: map[int]ucitest.Fault{0: ucitest.Hang}}
+	tr := &tournament{
+		engines:  []engineConfig{fakeEngine(t, hung), fakeEngine(t, hung)},
+		pairings: []pairing{{0, 1}},
+		openings: []opening{{}},
+		tc:       timeControl{base: 10 * time.Second},
+	}
+	w := tr.newWorker(0)
+	defer w.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if g, err := w.play(ctx, job{}); err == nil {
+		t.Errorf("play() = %v, expected an error", g.Result)
+	}
+}
+
+func TestPlayAdjudication(t *testing.T) {
+	tests := []struct {
+		white, black ucitest.Engine
+		adj          adjudication
+		result       string
+		termination  string
+		plies        int
+	}{
+		{
+			ucitest.Engine{Score: 600}, ucitest.Engine{Score: -600}, adjudication{resignScore: 500, resignMoves: 3},
+			"1-0", "resign adjudication", 6,
+		},
+		{
+			ucitest.Engine{Score: -600}, ucitest.Engine{Score: 600}, adjudication{resignScore: 500, resignMoves: 3},
+			"0-1", "resign adjudication", 6,
+		},
+		{
+			// They disagree, so the game goes to the move limit.
+			ucitest.Engine{Score: 600}, ucitest.Engine{Score: 600}, adjudication{resignScore: 500, resignMoves: 3, maxMoves: 5},
+			"1/2-1/2", "move limit", 10,
+		},
+		{
+			ucitest.Engine{Score: 5}, ucitest.Engine{Score: -5}, adjudication{drawScore: 10, drawMoves: 2, drawAfter: 2},
+			"1/2-1/2", "draw adjudication", 6,
+		},
+		{
+			// Engines that send scores mate the same way.
+			ucitest.Engine{Moves: foolsMate, Score: 5}, ucitest.Engine{Moves: foolsMate, Score: 5}, adjudication{drawScore: 10, drawMoves: 2, drawAfter: 2},
+			"0-1", "checkmate", 4,
+		},
+	}
+	for i, test := range tests {
+		tr := &tournament{
+			engines:  []engineConfig{fakeEngine(t, &test.white), fakeEngine(t, &test.black)},
+			pairings: []pairing{{0, 1}},
+			openings: []opening{{}},
+			tc:       timeControl{base: 10 * time.Second, margin: 100 * time.Millisecond},
+			adj:      test.adj,
+		}
+		w := tr.newWorker(0)
+		g, err := w.play(context.Background(), job{})
+		w.Close()
+		if err != nil {
+			t.Errorf("[%d] play() = %v", i, err)
+			continue
+		}
+		if g.Result != test.result || g.Tags["Termination"] != test.termination || len(g.Moves) != test.plies {
+			t.Errorf("[%d] play() = %v {%v} after %d plies, expected %v {%v} after %d", i,
+				g.Result, g.Tags["Termination"], len(g.Moves), test.result, test.termination, test.plies)
+		}
+	}
+}
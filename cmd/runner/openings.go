@@ -0,0 +1,98 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"chess"
+	"chess/notation"
+)
+
+// opening is a starting position for a game.
+type opening struct {
+	fen   string   // The starting FEN, or empty for the standard starting position.
+	moves []string // Moves, in long algebraic notation, played from fen.
+}
+
+// board returns the position at the end of the opening.
+func (o opening) board() (*chess.Board, error) {
+	b := chess.New()
+	if len(o.fen) != 0 {
+		var err error
+		if b, err = chess.FromFEN(o.fen); err != nil {
+			return nil, err
+		}
+	}
+	return b, b.ApplyMoves(o.moves)
+}
+
+// loadOpenings reads openings from a file. Files ending in .pgn are read as
+// PGN, with each game's moves being an opening, and all others as EPD, with
+// each line being an opening.
+func loadOpenings(path string) ([]opening, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening openings: %w", err)
+	}
+	defer f.Close()
+
+	var openings []opening
+	if strings.ToLower(filepath.Ext(path)) == ".pgn" {
+		openings, err = readPGNOpenings(f)
+	} else {
+		openings, err = readEPDOpenings(f)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading %v: %w", path, err)
+	}
+	if len(openings) == 0 {
+		return nil, fmt.Errorf("no openings in %v", path)
+	}
+	return openings, nil
+}
+
+// readEPDOpenings reads openings from EPD lines. Only the position is used,
+// and any operations are ignored.
+func readEPDOpenings(r io.Reader) ([]opening, error) {
+	epds, err := notation.ReadEPD(r)
+	if err != nil {
+		return nil, err
+	}
+	openings := make([]opening, len(epds))
+	for i, e := range epds {
+		openings[i] = opening{fen: e.FEN}
+	}
+	return openings, nil
+}
+
+// readPGNOpenings reads openings from the games in a PGN database.
+func readPGNOpenings(r io.Reader) ([]opening, error) {
+	var openings []opening
+	pr := notation.NewPGNReader(r)
+	for {
+		g, err := pr.Next()
+		if errors.Is(err, io.EOF) {
+			return openings, nil
+		} else if err != nil {
+			return nil, err
+		}
+		b, err := g.Board()
+		if err != nil {
+			return nil, fmt.Errorf("game %d: %w", len(openings)+1, err)
+		}
+		o := opening{fen: g.Tags["FEN"]}
+		for _, san := range g.Moves {
+			m, err := notation.ParseSAN(b, san)
+			if err != nil {
+				return nil, fmt.Errorf("game %d: %w", len(openings)+1, err)
+			}
+			o.moves = append(o.moves, m.UCIString())
+			b.MakeMove(m)
+		}
+		openings = append(openings, o)
+	}
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// stats tallies a match's results from the first engine's point of view.
+//
+// Games are played in pairs, with the engines swapping colors on the same
+// opening, and the pentanomial tally counts the pairs by their total score.
+// Pairs cancel out most of the bias from unbalanced openings, so the SPRT
+// uses them when it can.
+type stats struct {
+	wins, draws, losses int
+	penta               [5]int // Pairs scoring 0, 0.5, 1, 1.5, and 2 points.
+
+	pending map[int]float64 // Scores of pairs with only one game finished.
+}
+
+// add records the score of one of a pair's games (1 for a win, 0.5 for a draw,
+// and 0 for a loss). The games of a pair can be added in either order.
+func (s *stats) add(pair int, score float64) {
+	switch score {
+	case 1:
+		s.wins += 1
+	case 0.5:
+		s.draws += 1
+	default:
+		s.losses += 1
+	}
+	if s.pending == nil {
+		s.pending = make(map[int]float64)
+	}
+	if other, ok := s.pending[pair]; ok {
+		s.penta[int(2*(score+other))] += 1
+		delete(s.pending, pair)
+	} else {
+		s.pending[pair] = score
+	}
+}
+
+// games returns the number of games played.
+func (s *stats) games() int {
+	return s.wins + s.draws + s.losses
+}
+
+// pairs returns the number of complete game pairs.
+func (s *stats) pairs() (n int) {
+	for _, c := range s.penta {
+		n += c
+	}
+	return n
+}
+
+// score returns the mean score per game.
+func (s *stats) score() float64 {
+	if s.games() == 0 {
+		return 0.5
+	}
+	return (float64(s.wins) + 0.5*float64(s.draws)) / float64(s.games())
+}
+
+// scoreEpsilon keeps scores off 0 and 1. A clean sweep would otherwise be an
+// infinite Elo difference, with no variance.
+const scoreEpsilon = 1e-3
+
+// clampScore clamps a mean score to [scoreEpsilon, 1-scoreEpsilon].
+func clampScore(score float64) float64 {
+	return math.Min(math.Max(score, scoreEpsilon), 1-scoreEpsilon)
+}
+
+// eloDiff converts a mean score into an Elo difference.
+func eloDiff(score float64) float64 {
+	return -400 * math.Log10(1/clampScore(score)-1)
+}
+
+// eloScore converts an Elo difference into the expected mean score.
+func eloScore(elo float64) float64 {
+	return 1 / (1 + math.Pow(10, -elo/400))
+}
+
+// scoreVariance returns the mean and variance of the per game score, from the
+// pentanomial tally if there are pairs, and the trinomial tally if not. It
+// also returns the number of samples.
+func (s *stats) scoreVariance() (mean, variance float64, n int) {
+	var probs []float64
+	var counts []int
+	if n = s.pairs(); n != 0 {
+		probs, counts = []float64{0, 0.25, 0.5, 0.75, 1}, s.penta[:]
+	} else {
+		n = s.games()
+		probs, counts = []float64{0, 0.5, 1}, []int{s.losses, s.draws, s.wins}
+	}
+	if n == 0 {
+		return 0.5, 0, 0
+	}
+	for i, c := range counts {
+		mean += probs[i] * float64(c) / float64(n)
+	}
+	for i, c := range counts {
+		variance += (probs[i] - mean) * (probs[i] - mean) * float64(c) / float64(n)
+	}
+	return mean, variance, n
+}
+
+// elo returns the Elo difference between the engines, and the margin of its
+// 95% confidence interval.
+func (s *stats) elo() (elo, margin float64) {
+	mean, variance, n := s.scoreVariance()
+	if n == 0 {
+		return 0, 0
+	}
+	dev := 1.959964 * math.Sqrt(variance/float64(n))
+	lo, hi := eloDiff(mean-dev), eloDiff(mean+dev)
+	return eloDiff(mean), (hi - lo) / 2
+}
+
+// los returns the likelihood of superiority, the probability that the first
+// engine is stronger. Draws don't affect it.
+func (s *stats) los() float64 {
+	if s.wins+s.losses == 0 {
+		return 0.5
+	}
+	return 0.5 * (1 + math.Erf(float64(s.wins-s.losses)/math.Sqrt(2*float64(s.wins+s.losses))))
+}
+
+// sprt is a sequential probability ratio test of the hypotheses that the
+// first engine is elo0 (H0) or elo1 (H1) Elo stronger, with alpha and beta
+// being the acceptable false positive and false negative rates.
+type sprt struct {
+	elo0, elo1  float64
+	alpha, beta float64
+}
+
+// bounds returns the log likelihood ratios at which H0 and H1 are accepted.
+func (t sprt) bounds() (lower, upper float64) {
+	return math.Log(t.beta / (1 - t.alpha)), math.Log((1 - t.beta) / t.alpha)
+}
+
+// llr approximates the log likelihood ratio of the hypotheses given the
+// match's results, using the normal approximation of the generalized SPRT.
+// A clean sweep has no variance, so it's treated as a clamped score instead.
+func (t sprt) llr(s *stats) float64 {
+	mean, variance, n := s.scoreVariance()
+	if variance == 0 {
+		if n == 0 || (mean != 0 && mean != 1) {
+			return 0
+		}
+		mean = clampScore(mean)
+		variance = mean * (1 - mean)
+	}
+	s0, s1 := eloScore(t.elo0), eloScore(t.elo1)
+	return float64(n) * (s1 - s0) * (2*mean - s0 - s1) / (2 * variance)
+}
+
+// result returns "H0" or "H1" if a hypothesis has been accepted, and an empty
+// string if the test should continue.
+func (t sprt) result(s *stats) string {
+	llr := t.llr(s)
+	lower, upper := t.bounds()
+	switch {
+	case llr <= lower:
+		return "H0"
+	case llr >= upper:
+		return "H1"
+	}
+	return ""
+}
+
+// report returns a summary of the test's state.
+func (t sprt) report(s *stats) string {
+	lower, upper := t.bounds()
+	return fmt.Sprintf("SPRT: llr %.3g, lbound %.3g, ubound %.3g [%g, %g]", t.llr(s), lower, upper, t.elo0, t.elo1)
+}
+
+// points returns the number of points scored.
+func (s *stats) points() float64 {
+	return float64(s.wins) + 0.5*float64(s.draws)
+}
+
+// reversed returns the stats from the other engine's point of view.
+func (s *stats) reversed() stats {
+	r := stats{wins: s.losses, draws: s.draws, losses: s.wins}
+	for i, c := range s.penta {
+		r.penta[len(s.penta)-1-i] = c
+	}
+	return r
+}
+
+// merge adds another set of stats' games into s.
+func (s *stats) merge(o stats) {
+	s.wins += o.wins
+	s.draws += o.draws
+	s.losses += o.losses
+	for i, c := range o.penta {
+		s.penta[i] += c
+	}
+}
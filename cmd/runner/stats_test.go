@@ -0,0 +1,133 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	var s stats
+	for i, score := range []float64{1, 1, 1, 0, 0.5, 0, 0.5, 0.5, 1} {
+		s.add(i/2, score)
+	}
+	if s.wins != 4 || s.draws != 3 || s.losses != 2 {
+		t.Errorf("W/D/L = %d/%d/%d, expected 4/3/2", s.wins, s.draws, s.losses)
+	}
+	if expected := [5]int{0, 1, 2, 0, 1}; s.penta != expected {
+		t.Errorf("penta = %v, expected %v", s.penta, expected)
+	}
+	if s.pairs() != 4 || s.games() != 9 {
+		t.Errorf("pairs, games = %d, %d, expected 4, 9", s.pairs(), s.games())
+	}
+	if score := s.score(); math.Abs(score-5.5/9) > 1e-9 {
+		t.Errorf("score() = %v, expected %v", score, 5.5/9)
+	}
+	if elo, margin := s.elo(); elo <= 0 || margin <= 0 {
+		t.Errorf("elo() = %v, %v, expected positive values", elo, margin)
+	}
+
+	r := s.reversed()
+	if r.wins != 2 || r.draws != 3 || r.losses != 4 || r.penta != [5]int{1, 0, 2, 1, 0} {
+		t.Errorf("reversed() = %+v", r)
+	}
+
+	// Pairs can finish out of order.
+	s.add(10, 0)
+	s.add(5, 0)
+	s.add(10, 0.5)
+	if expected := [5]int{0, 2, 2, 0, 1}; s.penta != expected {
+		t.Errorf("penta = %v, expected %v", s.penta, expected)
+	}
+}
+
+func TestElo(t *testing.T) {
+	tests := []struct {
+		score, elo float64
+	}{
+		{0.5, 0},
+		{0.75, 190.849},
+		{0.25, -190.849},
+		{0.9, 381.697},
+	}
+	for i, test := range tests {
+		if elo := eloDiff(test.score); math.Abs(elo-test.elo) > 1e-3 {
+			t.Errorf("[%d] eloDiff(%v) = %v, expected %v", i, test.score, elo, test.elo)
+		}
+		if score := eloScore(test.elo); math.Abs(score-test.score) > 1e-6 {
+			t.Errorf("[%d] eloScore(%v) = %v, expected %v", i, test.elo, score, test.score)
+		}
+	}
+}
+
+func TestCleanSweep(t *testing.T) {
+	test := sprt{elo0: 0, elo1: 10, alpha: 0.05, beta: 0.05}
+	var s stats
+	for j := 0; j < 20; j++ {
+		s.add(j, 1)
+		s.add(j, 1)
+	}
+	tests := []struct {
+		s        stats
+		sign     float64
+		expected string
+	}{
+		{s, 1, "H1"},
+		{s.reversed(), -1, "H0"},
+	}
+	for i, tt := range tests {
+		elo, margin := tt.s.elo()
+		if math.IsInf(elo, 0) || math.IsNaN(elo) || elo*tt.sign <= 0 {
+			t.Errorf("[%d] elo() = %v, expected a finite Elo with sign %v", i, elo, tt.sign)
+		}
+		if math.IsInf(margin, 0) || math.IsNaN(margin) || margin < 0 {
+			t.Errorf("[%d] elo() margin = %v, expected a finite margin", i, margin)
+		}
+		if h := test.result(&tt.s); h != tt.expected {
+			t.Errorf("[%d] result() = %q (%v), expected %q", i, h, test.report(&tt.s), tt.expected)
+		}
+	}
+}
+
+func TestLOS(t *testing.T) {
+	tests := []struct {
+		wins, losses int
+		los          float64
+	}{
+		{0, 0, 0.5},
+		{10, 10, 0.5},
+		{15, 5, 0.987326},
+		{5, 15, 0.012674},
+	}
+	for i, test := range tests {
+		s := stats{wins: test.wins, losses: test.losses}
+		if los := s.los(); math.Abs(los-test.los) > 1e-5 {
+			t.Errorf("[%d] los() = %v, expected %v", i, los, test.los)
+		}
+	}
+}
+
+func TestSPRT(t *testing.T) {
+	test := sprt{elo0: 0, elo1: 10, alpha: 0.05, beta: 0.05}
+	tests := []struct {
+		pair     []float64 // Scores of each pair of games, repeated.
+		n        int
+		expected string
+	}{
+		{[]float64{1, 0.5}, 20, "H1"},
+		{[]float64{0, 0.5}, 20, "H0"},
+		{[]float64{1, 0}, 1000, ""},
+	}
+	for i, tt := range tests {
+		var s stats
+		for j := 0; j < tt.n; j++ {
+			s.add(2*j, tt.pair[0])
+			s.add(2*j, tt.pair[1])
+			// Vary the pairs a bit, so the variance isn't 0.
+			s.add(2*j+1, 0.5)
+			s.add(2*j+1, 0.5)
+		}
+		if h := test.result(&s); h != tt.expected {
+			t.Errorf("[%d] result() = %q (%v), expected %q", i, h, test.report(&s), tt.expected)
+		}
+	}
+}
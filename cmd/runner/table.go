@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// writeTables writes a tournament's ranking, with each engine's Elo relative
+// to the engines it played, followed by the crosstable of the points each
+// engine scored against each other.
+func writeTables(w io.Writer, names []string, pairings []pairing, results []stats) error {
+	// Tally each engine's games, and its results against each opponent.
+	totals := make([]stats, len(names))
+	cross := make([][]*stats, len(names))
+	for i := range cross {
+		cross[i] = make([]*stats, len(names))
+	}
+	for i, pr := range pairings {
+		s, r := results[i], results[i].reversed()
+		totals[pr.a].merge(s)
+		totals[pr.b].merge(r)
+		cross[pr.a][pr.b], cross[pr.b][pr.a] = &s, &r
+	}
+
+	order := make([]int, len(names))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return totals[order[i]].points() > totals[order[j]].points()
+	})
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "Rank\tName\tElo\t+/-\tGames\tScore\tDraws\t")
+	for rank, i := range order {
+		s := &totals[i]
+		elo, margin := s.elo()
+		fmt.Fprintf(tw, "%d\t%s\t%.1f\t%.1f\t%d\t%.1f%%\t%.1f%%\t\n", rank+1, names[i], elo, margin,
+			s.games(), 100*s.score(), 100*float64(s.draws)/float64(max(s.games(), 1)))
+	}
+	fmt.Fprintln(tw)
+
+	// The crosstable's columns are numbered by rank to keep it narrow.
+	header := []string{"", "Name"}
+	for rank := range order {
+		header = append(header, fmt.Sprint(rank+1))
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t")+"\t")
+	for rank, i := range order {
+		row := []string{fmt.Sprint(rank + 1), names[i]}
+		for _, j := range order {
+			switch s := cross[i][j]; {
+			case i == j:
+				row = append(row, "-")
+			case s == nil:
+				row = append(row, "")
+			default:
+				row = append(row, fmt.Sprintf("%g/%d", s.points(), s.games()))
+			}
+		}
+		fmt.Fprintln(tw, strings.Join(row, "\t")+"\t")
+	}
+	return tw.Flush()
+}
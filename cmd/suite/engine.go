@@ -0,0 +1,15 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+
+	"chess/uci"
+)
+
+// startEngine starts an engine, with its stderr going to ours.
+func startEngine(name string, args ...string) (*uci.Process, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Stderr = os.Stderr
+	return uci.StartProcess(cmd)
+}
@@ -0,0 +1,185 @@
+// Command suite runs an EPD test suite, such as WAC, ECM, or STS, against a
+// UCI engine.
+//
+//	suite [flags] suite.epd engine [arg...]
+//
+// Each position is solved by the engine's move matching its bm operation
+// (and not its am), and for dm positions, by the engine finding a mate that
+// short. The time to solution is when the engine settled on a solution.
+//
+// A baseline of the solved positions can be saved with -save, and compared
+// against with -baseline, listing the positions that regressed.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"chess/notation"
+	"chess/uci"
+)
+
+// options are UCI options, set with repeated -option flags.
+type options [][2]string
+
+func (o *options) String() string {
+	var s []string
+	for _, opt := range *o {
+		s = append(s, opt[0]+"="+opt[1])
+	}
+	return strings.Join(s, ",")
+}
+
+func (o *options) Set(v string) error {
+	name, value, ok := strings.Cut(v, "=")
+	if !ok {
+		return fmt.Errorf("expected name=value, got %q", v)
+	}
+	*o = append(*o, [2]string{name, value})
+	return nil
+}
+
+var (
+	depth        = flag.Int("depth", 0, "depth to search each position to, instead of -movetime")
+	moveTime     = flag.Duration("movetime", time.Second, "time to search each position for")
+	timeout      = flag.Duration("timeout", time.Minute, "time after which a -depth search is stopped")
+	baselineFile = flag.String("baseline", "", "baseline to compare the results with")
+	saveFile     = flag.String("save", "", "filename where a baseline of the results is written")
+	verbose      = flag.Bool("v", false, "print each position's result")
+	engineOpts   options
+)
+
+func init() {
+	flag.Var(&engineOpts, "option", "UCI option to set, as name=value (can be repeated)")
+}
+
+// report prints the results of a suite.
+func report(w io.Writer, epds []*notation.EPD, results []result) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	solved, total := 0, time.Duration(0)
+	for i, r := range results {
+		if r.solved {
+			solved++
+			total += r.time
+		}
+		if !*verbose {
+			continue
+		}
+		if r.solved {
+			fmt.Fprintf(tw, "%v\tsolved\t%v\t%v\n", r.id, r.move, r.time.Round(time.Millisecond))
+		} else {
+			fmt.Fprintf(tw, "%v\tfailed\t%v\texpected %v\n", r.id, r.move, expected(epds[i]))
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Solved %d of %d (%.1f%%)", solved, len(results), 100*float64(solved)/float64(max(len(results), 1)))
+	if solved != 0 {
+		fmt.Fprintf(w, ", mean time to solution %v", (total / time.Duration(solved)).Round(time.Millisecond))
+	}
+	_, err := fmt.Fprintln(w)
+	return err
+}
+
+// idList returns a count and list of position ids.
+func idList(ids []string) string {
+	if len(ids) == 0 {
+		return "0"
+	}
+	return fmt.Sprintf("%d (%v)", len(ids), strings.Join(ids, " "))
+}
+
+func main() {
+	flag.Parse()
+	if flag.NArg() < 2 {
+		log.Fatalf("usage: %v [flags] suite.epd engine [arg...]", os.Args[0])
+	}
+
+	f, err := os.Open(flag.Arg(0))
+	if err != nil {
+		log.Fatalf("error: %v", err)
+	}
+	epds, err := notation.ReadEPD(f)
+	f.Close()
+	if err != nil {
+		log.Fatalf("error reading %v: %v", flag.Arg(0), err)
+	}
+	var bl baseline
+	if len(*baselineFile) != 0 {
+		if bl, err = loadBaseline(*baselineFile); err != nil {
+			log.Fatalf("error: %v", err)
+		}
+	}
+
+	e, err := startEngine(flag.Arg(1), flag.Args()[2:]...)
+	if err != nil {
+		log.Fatalf("error: %v", err)
+	}
+	defer e.Close()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	setup, cancel := context.WithTimeout(ctx, 10*time.Second)
+	err = e.Handshake(setup)
+	for _, opt := range engineOpts {
+		if err == nil {
+			err = e.SetOption(opt[0], opt[1])
+		}
+	}
+	if err == nil {
+		err = e.IsReady(setup)
+	}
+	cancel()
+	if err != nil {
+		e.Close()
+		log.Fatalf("error starting engine: %v", err)
+	}
+
+	params, limit := uci.GoParams{MoveTime: *moveTime}, *moveTime+*timeout
+	if *depth != 0 {
+		params, limit = uci.GoParams{Depth: *depth}, *timeout
+	}
+	var results []result
+	for i, epd := range epds {
+		r, err := solve(ctx, e.Client, epd, params, limit)
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			e.Close()
+			log.Fatalf("error solving %v: %v", positionID(epd, i), err)
+		}
+		r.id = positionID(epd, i)
+		results = append(results, r)
+	}
+
+	if err := report(os.Stdout, epds, results); err != nil {
+		log.Fatalf("error: %v", err)
+	}
+	if bl != nil {
+		regressed, improved := bl.compare(results)
+		fmt.Printf("Regressed from the baseline: %v\n", idList(regressed))
+		fmt.Printf("Newly solved: %v\n", idList(improved))
+	}
+	if len(*saveFile) != 0 {
+		out, err := os.Create(*saveFile)
+		if err == nil {
+			err = writeBaseline(out, results)
+			if cerr := out.Close(); err == nil {
+				err = cerr
+			}
+		}
+		if err != nil {
+			e.Close()
+			log.Fatalf("error saving baseline: %v", err)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"chess"
+	"chess/notation"
+	"chess/uci"
+)
+
+// stopTimeout is how long an engine has to reply after it's told to stop.
+const stopTimeout = time.Second
+
+// result is the outcome of an engine's search of one of a suite's positions.
+type result struct {
+	id     string
+	solved bool
+	time   time.Duration // How long it took to find the solution, if solved.
+	move   string        // The engine's move, in SAN.
+}
+
+// positionID returns the id of the nth (from 0) position in a suite.
+func positionID(e *notation.EPD, n int) string {
+	if len(e.ID) != 0 {
+		return e.ID
+	}
+	return fmt.Sprintf("#%d", n+1)
+}
+
+// expected describes a position's solution.
+func expected(e *notation.EPD) string {
+	b, err := e.Board()
+	if err != nil {
+		return ""
+	}
+	var parts []string
+	sans := func(moves []chess.Move) string {
+		s := make([]string, len(moves))
+		for i, m := range moves {
+			s[i] = notation.SAN(b, m)
+		}
+		return strings.Join(s, " ")
+	}
+	if len(e.BestMoves) != 0 {
+		parts = append(parts, "bm "+sans(e.BestMoves))
+	}
+	if len(e.AvoidMoves) != 0 {
+		parts = append(parts, "am "+sans(e.AvoidMoves))
+	}
+	if e.Mate != 0 {
+		parts = append(parts, fmt.Sprintf("dm %d", e.Mate))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// solve has an engine search a position, and checks its move against the
+// position's bm and am operations, and its score against dm. A search that
+// doesn't finish within timeout is stopped.
+//
+// The time to solution is when the engine started consistently reporting a
+// solution, according to its info lines.
+func solve(ctx context.Context, c *uci.Client, e *notation.EPD, params uci.GoParams, timeout time.Duration) (result, error) {
+	var r result
+	b, err := e.Board()
+	if err != nil {
+		return r, err
+	}
+	if len(e.BestMoves) == 0 && len(e.AvoidMoves) == 0 && e.Mate == 0 {
+		return r, errors.New("no bm, am, or dm to solve")
+	}
+
+	isMove := func(s string, moves []chess.Move) bool {
+		return slices.ContainsFunc(moves, func(m chess.Move) bool { return m.UCIString() == s })
+	}
+	isSolution := func(move string, info uci.Info) bool {
+		if _, err := b.ParseMove(move); err != nil {
+			return false
+		}
+		if len(e.BestMoves) != 0 && !isMove(move, e.BestMoves) || isMove(move, e.AvoidMoves) {
+			return false
+		}
+		s := info.Score
+		return e.Mate == 0 || info.HasScore && s.IsMate && s.Mate > 0 && s.Mate <= e.Mate
+	}
+
+	if err := c.NewGame(ctx); err != nil {
+		return r, err
+	}
+	if err := c.Position(e.FEN, nil); err != nil {
+		return r, err
+	}
+	start := time.Now()
+	found := false
+	onInfo := func(info uci.Info) {
+		if len(info.PV) == 0 || !info.HasScore {
+			return
+		}
+		switch ok := isSolution(info.PV[0], info); {
+		case ok && !found:
+			found, r.time = true, info.Time
+			if r.time == 0 {
+				r.time = time.Since(start)
+			}
+		case !ok:
+			found = false
+		}
+	}
+
+	searchCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	bm, info, err := c.Go(searchCtx, params, onInfo)
+	if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+		// Collect the move of a search that ran too long.
+		if err := c.Stop(); err != nil {
+			return r, err
+		}
+		stopCtx, cancel := context.WithTimeout(ctx, stopTimeout)
+		defer cancel()
+		bm, info, err = c.Wait(stopCtx, onInfo)
+	}
+	if err != nil {
+		return r, err
+	}
+
+	r.move = bm.Move
+	if m, err := b.ParseMove(bm.Move); err == nil {
+		r.move = notation.SAN(b, m)
+	}
+	if r.solved = isSolution(bm.Move, info); r.solved && !found {
+		r.time = time.Since(start)
+	} else if !r.solved {
+		r.time = 0
+	}
+	return r, nil
+}
+
+// baseline is the time to solution of each of a suite's solved positions, by
+// id, from an earlier run.
+type baseline map[string]time.Duration
+
+// readBaseline reads a baseline, written by writeBaseline.
+func readBaseline(r io.Reader) (baseline, error) {
+	bl := make(baseline)
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		if len(strings.TrimSpace(line)) == 0 {
+			continue
+		}
+		id, ms, ok := strings.Cut(line, "\t")
+		v, err := strconv.ParseInt(ms, 10, 64)
+		if !ok || err != nil {
+			return nil, fmt.Errorf("line %d: invalid baseline: %q", n, line)
+		}
+		bl[id] = time.Duration(v) * time.Millisecond
+	}
+	return bl, scanner.Err()
+}
+
+// writeBaseline writes a baseline of the solved results, one "id\tms" line
+// per position, sorted by id.
+func writeBaseline(w io.Writer, results []result) error {
+	var lines []string
+	for _, r := range results {
+		if r.solved {
+			lines = append(lines, fmt.Sprintf("%s\t%d\n", r.id, r.time.Milliseconds()))
+		}
+	}
+	sort.Strings(lines)
+	for _, l := range lines {
+		if _, err := io.WriteString(w, l); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// compare returns the ids of the positions that were solved in the baseline
+// but not in results, and those that weren't but are now.
+func (bl baseline) compare(results []result) (regressed, improved []string) {
+	for _, r := range results {
+		_, wasSolved := bl[r.id]
+		switch {
+		case wasSolved && !r.solved:
+			regressed = append(regressed, r.id)
+		case !wasSolved && r.solved:
+			improved = append(improved, r.id)
+		}
+	}
+	return regressed, improved
+}
+
+// loadBaseline reads a baseline from a file.
+func loadBaseline(path string) (baseline, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening baseline: %w", err)
+	}
+	defer f.Close()
+	bl, err := readBaseline(f)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %v: %w", path, err)
+	}
+	return bl, nil
+}
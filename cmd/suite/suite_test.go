@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"chess/notation"
+	"chess/uci"
+	"chess/uci/ucitest"
+)
+
+func TestSolve(t *testing.T) {
+	const (
+		wac001 = `2rr3k/pp3pp1/1nnqbN1p/3pN3/2pP4/2P3Q1/PPB4P/R4RK1 w - - bm Qg6; id "WAC.001";`
+		mate2  = `r2qkb1r/pp2nppp/3p4/2pNN1B1/2BnP3/3P4/PPP2PPP/R2bK2R w KQkq - dm 2; bm Nf6+;`
+		avoid  = `rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - am f3 g4;`
+	)
+	tests := []struct {
+		epd    string
+		engine ucitest.Engine
+		solved bool
+		move   string
+	}{
+		{wac001, ucitest.Engine{Moves: map[int]string{0: "g3g6"}}, true, "Qg6"},
+		{wac001, ucitest.Engine{Moves: map[int]string{0: "g3h4"}}, false, "Qh4"},
+		{wac001, ucitest.Engine{Faults: map[int]ucitest.Fault{0: ucitest.Illegal}}, false, ucitest.IllegalMove},
+		{wac001, ucitest.Engine{Moves: map[int]string{0: "g3g6"}, Faults: map[int]ucitest.Fault{0: ucitest.BadInfo}}, true, "Qg6"},
+		{wac001, ucitest.Engine{Moves: map[int]string{0: "g3g6"}, Delay: 200 * time.Millisecond}, true, "Qg6"},
+		{mate2, ucitest.Engine{Moves: map[int]string{0: "d5f6"}, Mate: 2}, true, "Nf6+"},
+		{mate2, ucitest.Engine{Moves: map[int]string{0: "d5f6"}, Mate: 3}, false, "Nf6+"},
+		{mate2, ucitest.Engine{Moves: map[int]string{0: "d5f6"}, Score: 500}, false, "Nf6+"},
+		{mate2, ucitest.Engine{Moves: map[int]string{0: "e5f7"}, Mate: 2}, false, "Nxf7"},
+		{avoid, ucitest.Engine{Moves: map[int]string{0: "e2e4"}}, true, "e4"},
+		{avoid, ucitest.Engine{Moves: map[int]string{0: "g2g4"}}, false, "g4"},
+	}
+	for i, test := range tests {
+		e, err := notation.ParseEPD(test.epd)
+		if err != nil {
+			t.Fatalf("[%d] ParseEPD() = %v", i, err)
+		}
+		c, stop := test.engine.Client()
+		r, err := solve(context.Background(), c, e, uci.GoParams{Depth: 1}, 50*time.Millisecond)
+		stop()
+		if err != nil {
+			t.Errorf("[%d] solve() = %v", i, err)
+			continue
+		}
+		if r.solved != test.solved || r.move != test.move {
+			t.Errorf("[%d] solve() = %v %v, expected %v %v", i, r.solved, r.move, test.solved, test.move)
+		}
+		if r.solved != (r.time > 0) {
+			t.Errorf("[%d] solve() time = %v, solved = %v", i, r.time, r.solved)
+		}
+	}
+
+	// Positions need something to solve.
+	e, _ := notation.ParseEPD(`rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - id "start";`)
+	c, stop := (&ucitest.Engine{}).Client()
+	defer stop()
+	if _, err := solve(context.Background(), c, e, uci.GoParams{Depth: 1}, time.Second); err == nil {
+		t.Errorf("solve() = nil, expected an error with no bm, am, or dm")
+	}
+}
+
+func TestBaseline(t *testing.T) {
+	results := []result{
+		{id: "b", solved: true, time: 1500 * time.Millisecond},
+		{id: "a", solved: true, time: 20 * time.Millisecond},
+		{id: "c"},
+	}
+	var sb strings.Builder
+	if err := writeBaseline(&sb, results); err != nil {
+		t.Fatalf("writeBaseline() = %v", err)
+	}
+	if expected := "a\t20\nb\t1500\n"; sb.String() != expected {
+		t.Errorf("writeBaseline() = %q, expected %q", sb.String(), expected)
+	}
+	bl, err := readBaseline(strings.NewReader(sb.String()))
+	if err != nil {
+		t.Fatalf("readBaseline() = %v", err)
+	}
+	if expected := (baseline{"a": 20 * time.Millisecond, "b": 1500 * time.Millisecond}); !reflect.DeepEqual(bl, expected) {
+		t.Errorf("readBaseline() = %v, expected %v", bl, expected)
+	}
+	if _, err := readBaseline(strings.NewReader("a 20\n")); err == nil {
+		t.Errorf("readBaseline() = nil, expected an error")
+	}
+
+	results = []result{{id: "a", solved: true}, {id: "b"}, {id: "c", solved: true}, {id: "d"}}
+	regressed, improved := bl.compare(results)
+	if !reflect.DeepEqual(regressed, []string{"b"}) || !reflect.DeepEqual(improved, []string{"c"}) {
+		t.Errorf("compare() = %v, %v, expected [b], [c]", regressed, improved)
+	}
+}
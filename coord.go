@@ -1,4 +1,4 @@
-package main
+package chess
 
 import (
 	"errors"
@@ -201,7 +201,7 @@ func ToBit(c []Coord) (b Bit) {
 	return b
 }
 
-// TooCoordSlice returns a slice of Coord from a given Bit.
+// ToCoordSlice returns a slice of Coord from a given Bit, in index order.
 func (b Bit) ToCoordSlice() (c []Coord) {
 	c = make([]Coord, 0, b.CountOnes())
 	for b != 0 {
@@ -210,8 +210,9 @@ func (b Bit) ToCoordSlice() (c []Coord) {
 	return c
 }
 
+// NextCoord clears the lowest set bit, and returns its Coord.
 func (b *Bit) NextCoord() Coord {
-	i := 63 - bits.LeadingZeros64(uint64(*b))
-	b.Clear(i)
+	i := bits.TrailingZeros64(uint64(*b))
+	*b &= *b - 1
 	return CoordFromIdx(i)
 }
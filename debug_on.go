@@ -0,0 +1,6 @@
+//go:build chessdebug
+
+package chess
+
+// debugInvariants makes every Board check its invariants after each move.
+const debugInvariants = true
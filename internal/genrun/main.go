@@ -0,0 +1,54 @@
+// Command genrun runs one of the chess package's //go:build ignore
+// generators.
+//
+// The generators are package main programs built along with some of the chess
+// package's files, which `go run` won't mix. genrun copies the files into a
+// temporary directory, renaming their package to main, and runs them from the
+// current directory, so generated files are written next to the package.
+//
+//	go run ./internal/genrun piece_gen.go piece.go dir.go coord.go bit.go
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+)
+
+var packageRE = regexp.MustCompile(`(?m)^package chess$`)
+
+func main() {
+	flag.Parse()
+	if flag.NArg() == 0 {
+		log.Fatalf("usage: %v generator.go [files...]", os.Args[0])
+	}
+
+	dir, err := os.MkdirTemp("", "genrun")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var files []string
+	for _, path := range flag.Args() {
+		src, err := os.ReadFile(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		dst := filepath.Join(dir, filepath.Base(path))
+		if err := os.WriteFile(dst, packageRE.ReplaceAll(src, []byte("package main")), 0666); err != nil {
+			log.Fatal(err)
+		}
+		files = append(files, dst)
+	}
+
+	cmd := exec.Command("go", append([]string{"run"}, files...)...)
+	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		os.RemoveAll(dir)
+		log.Fatal(err)
+	}
+}
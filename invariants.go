@@ -0,0 +1,119 @@
+package chess
+
+import "fmt"
+
+// ComputeHash computes the board's hash from scratch. It should always equal
+// ZHash, which is maintained incrementally as moves are made.
+func (b *Board) ComputeHash() Hash {
+	return b.keys.hash(b)
+}
+
+// SetDebug turns on checking the board's invariants after every move made, so
+// perft and search panic at the move that corrupts it. It's always on when
+// built with the chessdebug tag.
+func (b *Board) SetDebug(v bool) {
+	b.debug = v || debugInvariants
+}
+
+// CheckInvariants cross-checks the board's incrementally maintained state
+// against the pieces on it, returning an error describing the first mismatch.
+func (b *Board) CheckInvariants() error {
+	if h, exp := b.state.hash, b.ComputeHash(); h != exp {
+		return fmt.Errorf("hash %016x, expected %016x", h, exp)
+	}
+	if h, exp := b.state.pawnHash, b.keys.pawnHash(b); h != exp {
+		return fmt.Errorf("pawn hash %016x, expected %016x", h, exp)
+	}
+
+	var wOcc, wSlider, bOcc, bSlider Bit
+	var score Score
+	var material MaterialKey
+	kings := map[Piece]Coord{White: InvalidCoord, Black: InvalidCoord}
+	for i, p := range b.state.spaces {
+		if p == Empty {
+			continue
+		}
+		occ, slider := &wOcc, &wSlider
+		if p.IsBlack() {
+			occ, slider = &bOcc, &bSlider
+		}
+		occ.Set(i)
+		if p.isSlider() {
+			slider.Set(i)
+		}
+		if p.IsKing() {
+			if c := kings[p.Color()]; c != InvalidCoord {
+				return fmt.Errorf("%v on %v and %v", p, c, CoordFromIdx(i))
+			}
+			kings[p.Color()] = CoordFromIdx(i)
+		}
+		score += p.Score()
+		material += p.materialKey()
+	}
+	for _, occ := range []struct {
+		name   string
+		v, exp Bit
+	}{
+		{"white occupancy", b.state.wOcc, wOcc},
+		{"white sliders", b.state.wSlider, wSlider},
+		{"black occupancy", b.state.bOcc, bOcc},
+		{"black sliders", b.state.bSlider, bSlider},
+	} {
+		if occ.v != occ.exp {
+			return fmt.Errorf("%s %016x, expected %016x", occ.name, uint64(occ.v), uint64(occ.exp))
+		}
+	}
+	if b.state.wkLoc != kings[White] {
+		return fmt.Errorf("white king location %v, expected %v", b.state.wkLoc, kings[White])
+	}
+	if b.state.bkLoc != kings[Black] {
+		return fmt.Errorf("black king location %v, expected %v", b.state.bkLoc, kings[Black])
+	}
+	if b.state.score != score {
+		return fmt.Errorf("score %v, expected %v", b.state.score, score)
+	}
+	if b.state.material != material {
+		return fmt.Errorf("material %012x, expected %012x", uint64(b.state.material), uint64(material))
+	}
+
+	// Castling needs the king and rook on their starting squares.
+	for i, can := range b.state.castleFlags() {
+		if can && !b.fenCastle[i] && !b.castlePieces(i) {
+			c := castles[i]
+			return fmt.Errorf("%s allowed without the king on %v and rook on %v", c.name, c.king, c.rook)
+		}
+	}
+	return nil
+}
+
+// castles are the castling rights, and the squares the king and rook start on
+// for each.
+var castles = [4]struct {
+	name       string
+	king, rook Coord
+	color      Piece
+}{
+	{"white O-O", CoordFromXY(4, 0), CoordFromXY(7, 0), White},
+	{"white O-O-O", CoordFromXY(4, 0), CoordFromXY(0, 0), White},
+	{"black O-O", CoordFromXY(4, 7), CoordFromXY(7, 7), Black},
+	{"black O-O-O", CoordFromXY(4, 7), CoordFromXY(0, 7), Black},
+}
+
+// castleFlags returns the castling rights, in the order of castles.
+func (s *BoardState) castleFlags() [4]bool {
+	return [4]bool{s.wOO, s.wOOO, s.bOO, s.bOOO}
+}
+
+// castlePieces returns true if the king and rook for castles[i] are on their
+// starting squares.
+func (b *Board) castlePieces(i int) bool {
+	c := castles[i]
+	return b.at(c.king) == c.color|King && b.at(c.rook) == c.color|Rook
+}
+
+// checkMove panics if the move left the board's invariants broken.
+func (b *Board) checkMove(m Move) {
+	if err := b.CheckInvariants(); err != nil {
+		panic(fmt.Sprintf("board corrupted by %v: %v", m.UCIString(), err))
+	}
+}
@@ -0,0 +1,100 @@
+package chess
+
+import (
+	"strings"
+	"testing"
+)
+
+const kiwipeteFEN = "r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1"
+
+func TestCheckInvariants(t *testing.T) {
+	tests := []struct {
+		corrupt func(b *Board)
+		err     string
+	}{
+		{func(b *Board) {}, ""},
+		{func(b *Board) { b.state.hash ^= 1 }, "hash"},
+		{func(b *Board) { b.state.wOcc.Set(CoordFromXY(3, 3).Idx()) }, "white occupancy"},
+		{func(b *Board) { b.state.bSlider.Clear(CoordFromXY(0, 7).Idx()) }, "black sliders"},
+		{func(b *Board) { b.state.wkLoc = CoordFromXY(4, 0) }, "white king location"},
+		{func(b *Board) { b.state.pawnHash ^= 1 }, "pawn hash"},
+		{func(b *Board) { b.state.score += 1 }, "score"},
+		{func(b *Board) { b.state.material += 1 }, "material"},
+		{func(b *Board) { b.state.spaces[CoordFromXY(3, 3).Idx()] = Black | King }, "hash"},
+		{func(b *Board) {
+			b.state.bOO = true
+			b.state.hash = b.ComputeHash()
+		}, "black O-O"},
+	}
+
+	for i, test := range tests {
+		b, err := FromFEN(kiwipeteFEN)
+		if err != nil {
+			t.Fatalf("[%d] FromFEN() = %v", i, err)
+		}
+		if err := b.ApplyMoves([]string{"e1d1", "e8f8"}); err != nil {
+			t.Fatalf("[%d] error applying moves: %v", i, err)
+		}
+		test.corrupt(b)
+		err = b.CheckInvariants()
+		if test.err == "" && err != nil {
+			t.Errorf("[%d] CheckInvariants() = %v, expected nil", i, err)
+		} else if test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)) {
+			t.Errorf("[%d] CheckInvariants() = %v, expected %q error", i, err, test.err)
+		}
+	}
+}
+
+func TestFENCastlingRights(t *testing.T) {
+	// Rights without the king and rook in place are kept, but not checked.
+	tests := []struct {
+		fen      string
+		moves    []string
+		expected string
+	}{
+		{"k7/8/8/8/8/8/8/R3K2R w KQkq - 0 1", []string{"e1g1"}, "k7/8/8/8/8/8/8/R4RK1 b kq - 1 1"},
+		{"1nbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", []string{"g1f3", "e7e6"},
+			"1nbqkbnr/pppp1ppp/4p3/8/8/5N2/PPPPPPPP/RNBQKB1R w KQkq - 0 2"},
+	}
+
+	for i, test := range tests {
+		b, err := FromFEN(test.fen)
+		if err != nil {
+			t.Fatalf("[%d] FromFEN() = %v", i, err)
+		}
+		b.SetDebug(true)
+		if err := b.ApplyMoves(test.moves); err != nil {
+			t.Fatalf("[%d] error applying moves: %v", i, err)
+		}
+		if err := b.CheckInvariants(); err != nil {
+			t.Errorf("[%d] CheckInvariants() = %v", i, err)
+		}
+		if fen := b.FENString(); fen != test.expected {
+			t.Errorf("[%d] FENString() = %q, expected %q", i, fen, test.expected)
+		}
+	}
+}
+
+func TestDebugPerft(t *testing.T) {
+	for _, opts := range [][]BoardOption{nil, {WithPolyglotHashing()}} {
+		b, err := FromFEN(kiwipeteFEN, opts...)
+		if err != nil {
+			t.Fatalf("FromFEN() = %v", err)
+		}
+		b.SetDebug(true)
+		if cnt := b.Perft(3, Quiet); cnt != 97862 {
+			t.Errorf("Perft(3) = %d, expected 97862", cnt)
+		}
+	}
+
+	// A corrupted board panics at the next move made, even checking legality.
+	defer func() {
+		if r, _ := recover().(string); !strings.HasPrefix(r, "board corrupted") {
+			t.Errorf("ApplyMoves(e2e4) recovered %q, expected a panic", r)
+		}
+	}()
+	b := New()
+	b.SetDebug(true)
+	b.state.score += 1
+	b.ApplyMoves([]string{"e2e4"})
+}
@@ -1,16 +1,140 @@
-package main
+package chess
 
-//go:generate go run magic_gen.go magic.go bit.go coord.go dir.go size.go
+//go:generate go run ./internal/genrun magic_gen.go magic.go bit.go coord.go dir.go
 
-// Goal:
-// magic := rookMagicBitboards[rookPos]
-// blockers := occupied & magic.preMask
-// cacheKey := (blockers * magic.magicValue) >> magic.rotate
-// return magic.mapping[cacheKey]
+import "math/bits"
 
-// Magic
+// Sliding pieces' attacks are looked up with magic bitboards: the pieces
+// blocking a square's rays, multiplied by the square's magic number, index a
+// table of the attacked squares.
+//
+// https://www.chessprogramming.org/Magic_Bitboards
+
+// Magic is how the blockers for a square are turned into an index in the
+// attack table.
 type Magic struct {
-	Mask  Bit
-	Value Bit
-	Shift uint
+	Mask   Bit // The squares that can block the piece.
+	Value  Bit // The magic number, or 0 to index with pext.
+	Shift  uint
+	Offset int // Where the square's attacks start in the table.
+}
+
+// index returns the index of an occupancy in the square's attacks.
+func (m *Magic) index(occ Bit) Bit {
+	if m.Value == 0 {
+		return pext(occ, m.Mask)
+	}
+	return ((occ & m.Mask) * m.Value) >> m.Shift
+}
+
+// pext gathers the bits of v selected by mask into the low bits of the
+// result, as x86's PEXT instruction does. It's the fallback for squares
+// without a working magic number, and the inverse of indexToBit.
+func pext(v, mask Bit) (r Bit) {
+	for bit := Bit(1); mask != 0; bit <<= 1 {
+		if v&mask&-mask != 0 {
+			r |= bit
+		}
+		mask &= mask - 1
+	}
+	return r
+}
+
+// indexToBit will project the bits of index onto the given mask.
+// So, if we have an mask of 0xF0F0, and an index of 0x1E, it will return 0x10E0.
+func indexToBit(index int, mask Bit) (r Bit) {
+	for i := 0; mask != 0; i++ {
+		b := Bit(1) << bits.TrailingZeros64(uint64(mask))
+		if index&(1<<i) != 0 {
+			r |= b
+		}
+		mask ^= b
+	}
+	return r
+}
+
+func doesBlock(f, r int, block Bit) bool {
+	return block&(Bit(1)<<(f+r*8)) != 0
+}
+
+func genRookMask(idx int) Bit {
+	row := Bit(0x7E << (8 * (idx / 8)))
+	col := Bit(0x0001010101010100 << (idx % 8))
+	b := row | col
+	b.Clear(idx)
+	return b
+}
+
+func genRookAttacks(sq int, block Bit) (b Bit) {
+	rk, fl := sq/8, sq%8
+	for r := rk + 1; r <= 7; r++ {
+		b |= (Bit(1) << (fl + r*8))
+		if doesBlock(fl, r, block) {
+			break
+		}
+	}
+	for r := rk - 1; r >= 0; r-- {
+		b |= (Bit(1) << (fl + r*8))
+		if doesBlock(fl, r, block) {
+			break
+		}
+	}
+	for f := fl + 1; f <= 7; f++ {
+		b |= (Bit(1) << (f + rk*8))
+		if doesBlock(f, rk, block) {
+			break
+		}
+	}
+	for f := fl - 1; f >= 0; f-- {
+		b |= (Bit(1) << (f + rk*8))
+		if doesBlock(f, rk, block) {
+			break
+		}
+	}
+	return b
+}
+
+func genBishopMask(idx int) (b Bit) {
+	for x, y := idx%8+1, idx/8+1; x < 7 && y < 7; x, y = x+1, y+1 {
+		b.Set(x + y*8)
+	}
+	for x, y := idx%8-1, idx/8+1; x > 0 && y < 7; x, y = x-1, y+1 {
+		b.Set(x + y*8)
+	}
+	for x, y := idx%8-1, idx/8-1; x > 0 && y > 0; x, y = x-1, y-1 {
+		b.Set(x + y*8)
+	}
+	for x, y := idx%8+1, idx/8-1; x < 7 && y > 0; x, y = x+1, y-1 {
+		b.Set(x + y*8)
+	}
+	return b
+}
+
+func genBishopAttacks(sq int, block Bit) (b Bit) {
+	rk, fl := sq/8, sq%8
+	for r, f := rk+1, fl+1; r <= 7 && f <= 7; r, f = r+1, f+1 {
+		b |= Bit(1) << (f + r*8)
+		if doesBlock(f, r, block) {
+			break
+		}
+	}
+	for r, f := rk+1, fl-1; r <= 7 && f >= 0; r, f = r+1, f-1 {
+		b |= Bit(1) << (f + r*8)
+		if doesBlock(f, r, block) {
+			break
+		}
+	}
+	for r, f := rk-1, fl+1; r >= 0 && f <= 7; r, f = r-1, f+1 {
+		b |= Bit(1) << (f + r*8)
+		if doesBlock(f, r, block) {
+			break
+		}
+	}
+	for r, f := rk-1, fl-1; r >= 0 && f >= 0; r, f = r-1, f-1 {
+		b |= (Bit(1) << (f + r*8))
+		if doesBlock(f, r, block) {
+			break
+		}
+	}
+	return b
 }
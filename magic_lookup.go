@@ -0,0 +1,57 @@
+package chess
+
+// The sliding pieces' attacks, built from the magic numbers in
+// magic_values.go when the package is initialized. Every square's attacks
+// share one table, at the offset in the square's Magic.
+var (
+	rookMagic, bishopMagic [64]Magic
+	slidingAttacks         []Bit
+)
+
+func init() {
+	size := 0
+	for i := 0; i < 64; i++ {
+		size += 1<<genRookMask(i).CountOnes() + 1<<genBishopMask(i).CountOnes()
+	}
+	slidingAttacks = make([]Bit, 0, size)
+	for i := 0; i < 64; i++ {
+		rookMagic[i] = newMagic(i, genRookMask(i), genRookAttacks, rookMagics[i], &slidingAttacks)
+	}
+	for i := 0; i < 64; i++ {
+		bishopMagic[i] = newMagic(i, genBishopMask(i), genBishopAttacks, bishopMagics[i], &slidingAttacks)
+	}
+}
+
+// newMagic appends the attacks for a square to a table. If the magic number
+// maps two blocker configurations with different attacks to the same index,
+// the square falls back to pext.
+func newMagic(sq int, mask Bit, attacks func(int, Bit) Bit, value Bit, table *[]Bit) Magic {
+	n := mask.CountOnes()
+	m := Magic{Mask: mask, Value: value, Shift: uint(64 - n), Offset: len(*table)}
+	*table = append(*table, make([]Bit, 1<<n)...)
+	bits := (*table)[m.Offset:]
+	for i := range bits {
+		occ := indexToBit(i, mask)
+		a := attacks(sq, occ)
+		key := m.index(occ)
+		if bits[key] == 0 {
+			bits[key] = a
+		} else if bits[key] != a {
+			*table = (*table)[:m.Offset]
+			return newMagic(sq, mask, attacks, 0, table)
+		}
+	}
+	return m
+}
+
+// rookBit returns the squares a rook attacks, given the board's occupancy.
+func rookBit(c Coord, occ Bit) Bit {
+	m := &rookMagic[c.Idx()]
+	return slidingAttacks[m.Offset+int(m.index(occ))]
+}
+
+// bishopBit returns the squares a bishop attacks, given the board's occupancy.
+func bishopBit(c Coord, occ Bit) Bit {
+	m := &bishopMagic[c.Idx()]
+	return slidingAttacks[m.Offset+int(m.index(occ))]
+}
@@ -1,4 +1,4 @@
-package main
+package chess
 
 import (
 	"reflect"
@@ -25,27 +25,79 @@ func TestRookMagic(t *testing.T) {
 
 	for _, test := range tests {
 		t.Logf("RookOccupancy(%v, %v) %v", test.loc, test.occ, test.moves)
-		locs := rookLookup(test.loc, test.occ)
+		locs := rookBit(test.loc, test.occ).ToCoordSlice()
 		if !reflect.DeepEqual(locs, test.moves) {
 			t.Errorf("RookOccupancy(%v, %v) = %v, expected %v", test.loc, test.occ, locs, test.moves)
 		}
 	}
 }
 
-func TestMagicEquivalence(t *testing.T) {
-	cmpBits := func(name string, coords [64][][]Coord, bits [64][]Bit) {
-		for i := range bits {
-			if len(bits[i]) != len(coords[i]) {
-				t.Fatalf("len(%sBits[%d]) != len(%sCoords[%d])", name, i, name, i)
+func TestMagicTables(t *testing.T) {
+	tests := []struct {
+		name    string
+		maskF   func(int) Bit
+		attacks func(int, Bit) Bit
+		magics  *[64]Magic
+		lookup  func(Coord, Bit) Bit
+	}{
+		{"rook", genRookMask, genRookAttacks, &rookMagic, rookBit},
+		{"bishop", genBishopMask, genBishopAttacks, &bishopMagic, bishopBit},
+	}
+	size := 0
+	for _, test := range tests {
+		for sq := 0; sq < 64; sq++ {
+			c := CoordFromIdx(sq)
+			mask := test.maskF(sq)
+			if m := test.magics[sq]; m.Mask != mask || m.Value == 0 {
+				t.Errorf("%sMagic[%v] = %+v, expected a magic number for mask %v", test.name, c, m, mask)
+			}
+			size += 1 << mask.CountOnes()
+
+			// Every set of blockers, along with pieces off the rays, which
+			// shouldn't matter.
+			for i := 0; i < 1<<mask.CountOnes(); i++ {
+				occ := indexToBit(i, mask)
+				expected := test.attacks(sq, occ)
+				if v := test.lookup(c, occ|^mask); v != expected {
+					t.Fatalf("%sBit(%v, %v) = %v, expected %v", test.name, c, occ, v, expected)
+				}
 			}
-			for j := range coords[i] {
-				if val := ToBit(coords[i][j]); bits[i][j] != val {
-					t.Fatalf("%sBits[%d][%d] = %v, expected %v – %v %v", name, i, j, bits[i][j], val,
-						coords[i][j], val)
+		}
+	}
+	if len(slidingAttacks) != size || cap(slidingAttacks) != size {
+		t.Errorf("len, cap(slidingAttacks) = %d, %d, expected %d", len(slidingAttacks), cap(slidingAttacks), size)
+	}
+}
+
+func TestMagicPext(t *testing.T) {
+	tests := []struct {
+		name    string
+		maskF   func(int) Bit
+		attacks func(int, Bit) Bit
+	}{
+		{"rook", genRookMask, genRookAttacks},
+		{"bishop", genBishopMask, genBishopAttacks},
+	}
+	for _, test := range tests {
+		for sq := 0; sq < 64; sq++ {
+			mask := test.maskF(sq)
+
+			// A magic number that doesn't work falls back to pext.
+			var table []Bit
+			m := newMagic(sq, mask, test.attacks, 1, &table)
+			if m.Value != 0 {
+				t.Errorf("newMagic(%d, %s) = %+v, expected a fallback to pext", sq, test.name, m)
+			}
+			for i := 0; i < 1<<mask.CountOnes(); i++ {
+				occ := indexToBit(i, mask)
+				if idx := pext(occ, mask); idx != Bit(i) {
+					t.Fatalf("pext(%v, %v) = %d, expected %d", occ, mask, idx, i)
+				}
+				expected := test.attacks(sq, occ)
+				if v := table[m.Offset+int(m.index(occ))]; v != expected {
+					t.Fatalf("%s pext attacks(%d, %v) = %v, expected %v", test.name, sq, occ, v, expected)
 				}
 			}
 		}
 	}
-	cmpBits("rook", rookCoords, rookBits)
-	cmpBits("bishop", bishopCoords, bishopBits)
 }
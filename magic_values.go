@@ -0,0 +1,137 @@
+package chess
+
+// Code generated by go generate. DO NOT EDIT.
+
+var rookMagics = [64]Bit{
+	0x01800140002a5080, // a1
+	0x0440200040001000, // b1
+	0x4900082000411300, // c1
+	0x8100042009001000, // d1
+	0x0480080104000280, // e1
+	0x0280010400020080, // f1
+	0x0080008001000200, // g1
+	0x0600003400810042, // h1
+	0x0103800040068028, // a2
+	0x0015004000230088, // b2
+	0x4002001882022040, // c2
+	0x4008801000810800, // d2
+	0x0041001006080100, // e2
+	0x000a000902008410, // f2
+	0x0641000100040200, // g2
+	0x0001002186460100, // h2
+	0x22108a8004400020, // a3
+	0x4000404000201008, // b3
+	0x0011010010200841, // c3
+	0xc888018010004881, // d3
+	0x062c450011000800, // e3
+	0x0601010008020400, // f3
+	0x2e00040010018248, // g3
+	0x0004020001108044, // h3
+	0x0800400080208000, // a4
+	0x4100200040100040, // b4
+	0x0010080020040021, // c4
+	0x4380080080100080, // d4
+	0x0000050100080010, // e4
+	0x1002000e00100428, // f4
+	0x0000014400680250, // g4
+	0x0006008200040061, // h4
+	0x0080002000404000, // a5
+	0x4000400088802000, // b5
+	0x000181a002801009, // c5
+	0x1307002409001000, // d5
+	0x1008020040400400, // e5
+	0x4002020080800400, // f5
+	0x008050a504001208, // g5
+	0x4100011082001054, // h5
+	0x88094001e1828000, // a6
+	0x0210002000484000, // b6
+	0x0880200010008080, // c6
+	0x1008002010010100, // d6
+	0x0012002008120004, // e6
+	0x4c82019004020008, // f6
+	0xa88a2132100400a8, // g6
+	0x00021100408a0004, // h6
+	0x0000800449002700, // a7
+	0x0407004000816500, // b7
+	0x0000200010008480, // c7
+	0x0200100100200900, // d7
+	0xc061800400080180, // e7
+	0x4020800200140180, // f7
+	0x0a00101a29480400, // g7
+	0x2800009044010200, // h7
+	0x0040604300108003, // a8
+	0x8004241500400081, // b8
+	0x01400c2001001041, // c8
+	0x2081002004100009, // d8
+	0x8805004800021025, // e8
+	0x20a5009814002201, // f8
+	0x2200010200902804, // g8
+	0x0111041288410122, // h8
+}
+
+var bishopMagics = [64]Bit{
+	0xb020200119010010, // a1
+	0x0084044404002000, // b1
+	0x0049240400800000, // c1
+	0x8024040284081848, // d1
+	0x8201104040208310, // e1
+	0x3482080288080508, // f1
+	0x220a545008080004, // g1
+	0x02c0820050020811, // h1
+	0x8010680204082208, // a2
+	0x0800039002020440, // b2
+	0x0000040102020500, // c2
+	0x080004104e002040, // d2
+	0x040202121004a000, // e2
+	0x1030189050080404, // f2
+	0x800001044d0c4000, // g2
+	0x0500008401015020, // h2
+	0x881060550c100400, // a3
+	0x0620141091120084, // b3
+	0x4191100808010010, // c3
+	0x009024080240c020, // d3
+	0x1014201202010000, // e3
+	0x0808100d00481400, // f3
+	0x4001002400829000, // g3
+	0x0220220084010840, // h3
+	0x8004201440230444, // a4
+	0x1818420008100900, // b4
+	0x04080a00204600c1, // c4
+	0x10202080080084c0, // d4
+	0x2441001001004001, // e4
+	0x0430008481004100, // f4
+	0x400c040040424220, // g4
+	0x0a0100470d104800, // h4
+	0x1121080800a2a080, // a5
+	0x0004102440020c00, // b5
+	0x02c140300a220400, // c5
+	0x0000208021080200, // d5
+	0x8481010400020021, // e5
+	0x0420008080040a10, // f5
+	0xa0c104090804a800, // g5
+	0x0048210028b90880, // h5
+	0x0204108808010401, // a6
+	0x02140c2202041908, // b6
+	0x0020420040400400, // c6
+	0x1600004200804801, // d6
+	0x0700080100418400, // e6
+	0x208d501004400880, // f6
+	0x10491009024a2210, // g6
+	0x020812208200a422, // h6
+	0x9a04009805100200, // a7
+	0x40010a9890280002, // b7
+	0x0000824424240008, // c7
+	0x0004004904880812, // d7
+	0x2100212082048022, // e7
+	0x0080c00244010080, // f7
+	0x1188038404040008, // g7
+	0x0088503100410112, // h7
+	0x0820828490100200, // a8
+	0x02040024031c1080, // b8
+	0x0820800100824100, // c8
+	0x0000006011209810, // d8
+	0x8084000250a04841, // e8
+	0x8000081302100701, // f8
+	0x80801034a1080201, // g8
+	0x8040080380820bc4, // h8
+}
@@ -0,0 +1,42 @@
+package chess
+
+// MaterialKey is a signature of the material on the board: the count of each
+// kind of piece, packed 4 bits apiece. Positions with the same material have
+// the same key, so evaluation terms that depend only on the material can be
+// cached by it.
+type MaterialKey uint64
+
+// materialKey returns the key of a single piece.
+func (p Piece) materialKey() MaterialKey {
+	if p == Empty {
+		return 0
+	}
+	return 1 << (4 * (p.HashIdx() / 64))
+}
+
+// Count returns the number of pieces p in the material.
+func (k MaterialKey) Count(p Piece) int {
+	return int(k>>(4*(p.HashIdx()/64))) & 0xf
+}
+
+// MaterialKey returns the signature of the material on the board.
+func (b *Board) MaterialKey() MaterialKey {
+	return b.state.material
+}
+
+// PawnHash returns the hash of just the pawns on the board, for caching pawn
+// structure evaluation.
+func (b *Board) PawnHash() Hash {
+	return b.state.pawnHash
+}
+
+// Pieces returns the squares holding the given piece.
+func (b *Board) Pieces(p Piece) (v Bit) {
+	for occ := b.occupancy(p); occ != 0; {
+		c := occ.NextCoord()
+		if b.at(c) == p {
+			v.Set(c.Idx())
+		}
+	}
+	return v
+}
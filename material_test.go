@@ -0,0 +1,82 @@
+package chess
+
+import "testing"
+
+func TestMaterialKey(t *testing.T) {
+	tests := []struct {
+		fen    string
+		counts map[Piece]int
+	}{
+		{StartingFEN, map[Piece]int{
+			White | Pawn: 8, White | Knight: 2, White | Bishop: 2, White | Rook: 2, White | Queen: 1, White | King: 1,
+			Black | Pawn: 8, Black | Knight: 2, Black | Bishop: 2, Black | Rook: 2, Black | Queen: 1, Black | King: 1,
+		}},
+		{"k7/8/8/8/8/8/8/K7 w - - 0 1", map[Piece]int{White | King: 1, Black | King: 1}},
+		{"kQQQQQQQ/QQQQQQQQ/8/8/8/8/8/K7 w - - 0 1", map[Piece]int{White | Queen: 15, White | King: 1, Black | King: 1}},
+	}
+
+	for i, test := range tests {
+		b, err := FromFEN(test.fen)
+		if err != nil {
+			t.Fatalf("[%d] FromFEN() = %v", i, err)
+		}
+		for _, p := range []Piece{Pawn, Knight, Bishop, Rook, Queen, King} {
+			for _, color := range []Piece{White, Black} {
+				if cnt := b.MaterialKey().Count(p | color); cnt != test.counts[p|color] {
+					t.Errorf("[%d] Count(%v) = %d, expected %d", i, p|color, cnt, test.counts[p|color])
+				}
+			}
+		}
+	}
+}
+
+func TestMaterialAndPawnHash(t *testing.T) {
+	tests := []struct {
+		fen      string
+		moves    []string
+		expected string
+	}{
+		// Capture.
+		{StartingFEN, []string{"e2e4", "d7d5", "e4d5"}, "rnbqkbnr/ppp1pppp/8/3P4/8/8/PPPP1PPP/RNBQKBNR b KQkq - 0 3"},
+		// En passant.
+		{StartingFEN, []string{"e2e4", "a7a6", "e4e5", "d7d5", "e5d6"}, "rnbqkbnr/1pp1pppp/p2P4/8/8/8/PPPP1PPP/RNBQKBNR b KQkq - 0 3"},
+		// Promotion with capture.
+		{"1r5k/P7/8/8/8/8/8/K7 w - - 0 1", []string{"a7b8n"}, "1N5k/8/8/8/8/8/8/K7 b - - 0 1"},
+	}
+
+	for i, test := range tests {
+		b, err := FromFEN(test.fen)
+		if err != nil {
+			t.Fatalf("[%d] FromFEN() = %v", i, err)
+		}
+		if err := b.ApplyMoves(test.moves); err != nil {
+			t.Fatalf("[%d] error applying moves: %v", i, err)
+		}
+		expected, err := FromFEN(test.expected)
+		if err != nil {
+			t.Fatalf("[%d] FromFEN() = %v", i, err)
+		}
+		if k, exp := b.MaterialKey(), expected.MaterialKey(); k != exp {
+			t.Errorf("[%d] MaterialKey() = %012x, expected %012x", i, uint64(k), uint64(exp))
+		}
+		if h, exp := b.PawnHash(), expected.PawnHash(); h != exp {
+			t.Errorf("[%d] PawnHash() = %016x, expected %016x", i, h, exp)
+		}
+	}
+
+	// Piece moves don't change the pawn hash.
+	b := New()
+	h := b.PawnHash()
+	if err := b.ApplyMoves([]string{"g1f3", "g8f6"}); err != nil {
+		t.Fatalf("error applying moves: %v", err)
+	}
+	if b.PawnHash() != h {
+		t.Errorf("PawnHash() changed after knight moves")
+	}
+	if err := b.ApplyMoves([]string{"e2e4"}); err != nil {
+		t.Fatalf("error applying moves: %v", err)
+	}
+	if b.PawnHash() == h {
+		t.Errorf("PawnHash() unchanged after a pawn move")
+	}
+}
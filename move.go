@@ -1,6 +1,9 @@
-package main
+package chess
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
 
 type Move struct {
 	p           Piece
@@ -16,6 +19,43 @@ func (m *Move) Color() Piece {
 	return m.p.Color()
 }
 
+// Piece returns the piece that's moving.
+func (m *Move) Piece() Piece {
+	return m.p
+}
+
+// From returns where the piece is moving from.
+func (m *Move) From() Coord {
+	return m.from
+}
+
+// To returns where the piece is moving to. For castling, that's the king's
+// destination.
+func (m *Move) To() Coord {
+	return m.to
+}
+
+// Promotion returns the piece a pawn promotes to, or Empty if the move isn't
+// a promotion.
+func (m *Move) Promotion() Piece {
+	return m.promotion
+}
+
+// IsCapture returns true if the move captures a piece, including en passant.
+func (m *Move) IsCapture() bool {
+	return m.isCapture
+}
+
+// IsEnPassant returns true if the move is an en passant capture.
+func (m *Move) IsEnPassant() bool {
+	return m.isEnPassant
+}
+
+// IsCheck returns true if the move gives check.
+func (m *Move) IsCheck() bool {
+	return m.isCheck
+}
+
 // IsPromotion returns true if the move would be a promotion – it has nothing
 // to do with the promotion field.
 func (m *Move) IsPromotion() bool {
@@ -142,6 +182,12 @@ func (m Move) longAlgebraicString() string {
 	return fmt.Sprintf("%s%s%s", m.from.String(), m.to.String(), promo)
 }
 
+// UCIString returns the move in the long algebraic notation used by UCI, eg
+// "e2e4", "e1g1", or "e7e8q".
+func (m Move) UCIString() string {
+	return m.longAlgebraicString()
+}
+
 // String returns a string for the given Move. Note that it doesn't handle
 // ambiguous moves, eg Nef4.
 func (m Move) String() string {
@@ -161,3 +207,49 @@ func (m Move) IsVertical() bool {
 func (m Move) IsHorizontal() bool {
 	return m.to.Y() == m.from.Y()
 }
+
+// moveFlags are the bits for a Move's booleans in its binary encoding.
+const (
+	moveCapture = 1 << iota
+	moveEnPassant
+	moveCheck
+)
+
+// MarshalBinary encodes the move in 5 bytes: the piece, from, to, promotion
+// and flags.
+func (m Move) MarshalBinary() ([]byte, error) {
+	var flags byte
+	if m.isCapture {
+		flags |= moveCapture
+	}
+	if m.isEnPassant {
+		flags |= moveEnPassant
+	}
+	if m.isCheck {
+		flags |= moveCheck
+	}
+	return []byte{byte(m.p), byte(m.from), byte(m.to), byte(m.promotion), flags}, nil
+}
+
+// UnmarshalBinary decodes a move encoded by MarshalBinary.
+func (m *Move) UnmarshalBinary(data []byte) error {
+	if len(data) != 5 {
+		return fmt.Errorf("invalid move encoding length: %d", len(data))
+	}
+	*m = Move{
+		p:           Piece(data[0]),
+		from:        Coord(data[1]),
+		to:          Coord(data[2]),
+		promotion:   Piece(data[3]),
+		isCapture:   data[4]&moveCapture != 0,
+		isEnPassant: data[4]&moveEnPassant != 0,
+		isCheck:     data[4]&moveCheck != 0,
+	}
+	if m.IsNull() {
+		return nil
+	}
+	if !m.from.IsValid() || !m.to.IsValid() || int(m.p) >= len(scores) || int(m.promotion) >= len(scores) {
+		return errors.New("invalid move encoding")
+	}
+	return nil
+}
@@ -1,4 +1,4 @@
-package main
+package chess
 
 import "testing"
 
@@ -112,3 +112,32 @@ func TestPromotionString(t *testing.T) {
 		}
 	}
 }
+
+func TestMoveMarshalBinary(t *testing.T) {
+	for i, fen := range []string{StartingFEN, kiwipeteFEN, "r6k/1P6/8/8/8/8/8/K7 w - - 0 1", "k7/8/8/pP6/8/8/8/K7 w - a6 0 1"} {
+		b, err := FromFEN(fen)
+		if err != nil {
+			t.Fatalf("[%d] FromFEN() = %v", i, err)
+		}
+		for _, m := range append(b.PossibleMoves(nil), Move{}) {
+			data, err := m.MarshalBinary()
+			if err != nil {
+				t.Fatalf("[%d] MarshalBinary(%v) = %v", i, m, err)
+			}
+			var got Move
+			if err := got.UnmarshalBinary(data); err != nil {
+				t.Fatalf("[%d] UnmarshalBinary(%v) = %v", i, m, err)
+			}
+			if got != m {
+				t.Errorf("[%d] UnmarshalBinary(MarshalBinary(%v)) = %v", i, m, got)
+			}
+		}
+	}
+
+	var m Move
+	for _, data := range [][]byte{{1, 2, 3}, {byte(Pawn), 8, 64, 0, 0}, {100, 8, 16, 0, 0}} {
+		if err := m.UnmarshalBinary(data); err == nil {
+			t.Errorf("UnmarshalBinary(%v) = nil, expected error", data)
+		}
+	}
+}
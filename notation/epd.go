@@ -0,0 +1,152 @@
+package notation
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"chess"
+)
+
+// EPD is a position in Extended Position Description, with its operations.
+//
+// https://www.chessprogramming.org/Extended_Position_Description
+type EPD struct {
+	FEN        string       // The position, with its move counters from hmvc and fmvn.
+	ID         string       // id: the position's name.
+	BestMoves  []chess.Move // bm: the moves that solve the position.
+	AvoidMoves []chess.Move // am: the moves that don't.
+	Mate       int          // dm: the number of moves to mate, or 0.
+	Comment    string       // c0
+
+	// Ops are all the position's operations, including those above, with the
+	// quotes removed from string operands.
+	Ops map[string][]string
+}
+
+// Board returns the EPD's position.
+func (e *EPD) Board() (*chess.Board, error) {
+	return chess.FromFEN(e.FEN)
+}
+
+// ParseEPD parses a line of EPD.
+func ParseEPD(line string) (*EPD, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 4 {
+		return nil, fmt.Errorf("invalid EPD, missing position: %q", line)
+	}
+	ops, err := epdOps(strings.Join(fields[4:], " "))
+	if err != nil {
+		return nil, fmt.Errorf("invalid EPD %q: %w", line, err)
+	}
+	e := &EPD{Ops: ops}
+
+	// Fill out the FEN with the move counters.
+	hmvc, fmvn := "0", "1"
+	if v := ops["hmvc"]; len(v) == 1 {
+		hmvc = v[0]
+	}
+	if v := ops["fmvn"]; len(v) == 1 {
+		fmvn = v[0]
+	}
+	e.FEN = strings.Join(append(fields[:4:4], hmvc, fmvn), " ")
+	b, err := e.Board()
+	if err != nil {
+		return nil, err
+	}
+
+	moves := func(op string) ([]chess.Move, error) {
+		var moves []chess.Move
+		for _, san := range ops[op] {
+			m, err := ParseSAN(b, san)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %v move in EPD %q: %w", op, line, err)
+			}
+			moves = append(moves, m)
+		}
+		return moves, nil
+	}
+	if e.BestMoves, err = moves("bm"); err != nil {
+		return nil, err
+	}
+	if e.AvoidMoves, err = moves("am"); err != nil {
+		return nil, err
+	}
+	if v := ops["dm"]; len(v) != 0 {
+		if e.Mate, err = strconv.Atoi(v[0]); err != nil || e.Mate <= 0 {
+			return nil, fmt.Errorf("invalid dm in EPD %q", line)
+		}
+	}
+	e.ID = strings.Join(ops["id"], " ")
+	e.Comment = strings.Join(ops["c0"], " ")
+	return e, nil
+}
+
+// epdOps parses EPD operations, each an opcode followed by its operands, and
+// terminated with a semicolon.
+func epdOps(s string) (map[string][]string, error) {
+	ops := make(map[string][]string)
+	var op []string
+	var quoted bool
+	var sb strings.Builder
+	endWord := func() {
+		if sb.Len() != 0 {
+			op = append(op, sb.String())
+			sb.Reset()
+		}
+	}
+	for _, c := range s {
+		switch {
+		case quoted && c == '"':
+			op = append(op, sb.String())
+			sb.Reset()
+			quoted = false
+		case quoted:
+			sb.WriteRune(c)
+		case c == '"':
+			endWord()
+			quoted = true
+		case c == ';':
+			endWord()
+			if len(op) == 0 {
+				return nil, errors.New("missing opcode")
+			}
+			ops[op[0]] = op[1:]
+			op = nil
+		case c == ' ' || c == '\t':
+			endWord()
+		default:
+			sb.WriteRune(c)
+		}
+	}
+	endWord()
+	switch {
+	case quoted:
+		return nil, errors.New("unterminated string")
+	case len(op) != 0:
+		return nil, fmt.Errorf("unterminated operation %q", op[0])
+	}
+	return ops, nil
+}
+
+// ReadEPD reads a file of EPD, one position per line. Blank lines, and lines
+// starting with '#', are skipped.
+func ReadEPD(r io.Reader) ([]*EPD, error) {
+	var epds []*EPD
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+		e, err := ParseEPD(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		epds = append(epds, e)
+	}
+	return epds, scanner.Err()
+}
@@ -0,0 +1,99 @@
+package notation
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"chess"
+)
+
+func TestParseEPD(t *testing.T) {
+	tests := []struct {
+		line    string
+		fen     string
+		id      string
+		bm, am  []string
+		mate    int
+		comment string
+		ops     map[string][]string
+		isErr   bool
+	}{
+		{
+			line: `2rr3k/pp3pp1/1nnqbN1p/3pN3/2pP4/2P3Q1/PPB4P/R4RK1 w - - bm Qg6; id "WAC.001";`,
+			fen:  "2rr3k/pp3pp1/1nnqbN1p/3pN3/2pP4/2P3Q1/PPB4P/R4RK1 w - - 0 1",
+			id:   "WAC.001", bm: []string{"Qg6"},
+			ops: map[string][]string{"bm": {"Qg6"}, "id": {"WAC.001"}},
+		},
+		{
+			line: `r1b1kb1r/3q1ppp/pBp1pn2/8/Np3P2/5B2/PPP3PP/R2Q1RK1 w kq - bm Bxc6+ Nc5; am Qd3; c0 "a comment; with a semicolon"; hmvc 3; fmvn 15;`,
+			fen:  "r1b1kb1r/3q1ppp/pBp1pn2/8/Np3P2/5B2/PPP3PP/R2Q1RK1 w kq - 3 15",
+			bm:   []string{"Bxc6", "Nc5"}, am: []string{"Qd3"}, comment: "a comment; with a semicolon",
+			ops: map[string][]string{
+				"bm": {"Bxc6+", "Nc5"}, "am": {"Qd3"}, "c0": {"a comment; with a semicolon"}, "hmvc": {"3"}, "fmvn": {"15"},
+			},
+		},
+		{
+			line: "r2qkb1r/pp2nppp/3p4/2pNN1B1/2BnP3/3P4/PPP2PPP/R2bK2R w KQkq - dm 2;",
+			fen:  "r2qkb1r/pp2nppp/3p4/2pNN1B1/2BnP3/3P4/PPP2PPP/R2bK2R w KQkq - 0 1",
+			mate: 2, ops: map[string][]string{"dm": {"2"}},
+		},
+		{
+			line: "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq -",
+			fen:  "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1",
+			ops:  map[string][]string{},
+		},
+		{line: "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq", isErr: true},
+		{line: "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - bm Nf6;", isErr: true},
+		{line: "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - bm e4", isErr: true},
+		{line: `rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - id "start;`, isErr: true},
+		{line: "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - ;", isErr: true},
+		{line: "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - dm x;", isErr: true},
+	}
+	for i, test := range tests {
+		e, err := ParseEPD(test.line)
+		if isErr := err != nil; isErr != test.isErr {
+			t.Errorf("[%d] ParseEPD(%q) = %v, expected error %v", i, test.line, err, test.isErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if e.FEN != test.fen || e.ID != test.id || e.Mate != test.mate || e.Comment != test.comment {
+			t.Errorf("[%d] ParseEPD(%q) = %+v", i, test.line, e)
+		}
+		b, err := e.Board()
+		if err != nil {
+			t.Fatalf("[%d] Board() = %v", i, err)
+		}
+		sans := func(moves []chess.Move) []string {
+			var s []string
+			for _, m := range moves {
+				s = append(s, SAN(b, m))
+			}
+			return s
+		}
+		if v := sans(e.BestMoves); !reflect.DeepEqual(v, test.bm) {
+			t.Errorf("[%d] BestMoves = %v, expected %v", i, v, test.bm)
+		}
+		if v := sans(e.AvoidMoves); !reflect.DeepEqual(v, test.am) {
+			t.Errorf("[%d] AvoidMoves = %v, expected %v", i, v, test.am)
+		}
+		if !reflect.DeepEqual(e.Ops, test.ops) {
+			t.Errorf("[%d] Ops = %v, expected %v", i, e.Ops, test.ops)
+		}
+	}
+}
+
+func TestReadEPD(t *testing.T) {
+	epds, err := ReadEPD(strings.NewReader("# A comment.\n\n8/8/8/8/8/8/k7/K6R w - - id \"one\";\n  8/8/8/8/8/8/k7/K6R b - - id \"two\";\n"))
+	if err != nil {
+		t.Fatalf("ReadEPD() = %v", err)
+	}
+	if len(epds) != 2 || epds[0].ID != "one" || epds[1].ID != "two" {
+		t.Errorf("ReadEPD() = %v, expected positions one and two", epds)
+	}
+	if _, err := ReadEPD(strings.NewReader("8/8/8/8/8/8/k7/K6R w - - id \"one\";\nbad\n")); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("ReadEPD() = %v, expected an error on line 2", err)
+	}
+}
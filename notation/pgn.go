@@ -0,0 +1,277 @@
+package notation
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"chess"
+)
+
+// Game is a chess game, as read from PGN.
+//
+// https://www.saremba.de/chessgml/standards/pgn/pgn-complete.htm
+type Game struct {
+	Tags   map[string]string
+	Moves  []string // Moves in SAN.
+	Result string   // One of "1-0", "0-1", "1/2-1/2", or "*".
+}
+
+// isResult returns true if a token is a game termination marker.
+func isResult(tok string) bool {
+	switch tok {
+	case "1-0", "0-1", "1/2-1/2", "*":
+		return true
+	}
+	return false
+}
+
+// Board returns the game's starting position.
+func (g *Game) Board() (*chess.Board, error) {
+	if fen, ok := g.Tags["FEN"]; ok {
+		return chess.FromFEN(fen)
+	}
+	return chess.New(), nil
+}
+
+// sevenTagRoster are the tags every PGN game has, in the order they're written.
+var sevenTagRoster = []string{"Event", "Site", "Date", "Round", "White", "Black", "Result"}
+
+// WriteTo writes the game in PGN export format: the Seven Tag Roster first,
+// followed by the other tags in alphabetical order, and the movetext wrapped
+// at 80 columns.
+func (g *Game) WriteTo(w io.Writer) (int64, error) {
+	var sb strings.Builder
+	writeTag := func(name, value string) {
+		value = strings.ReplaceAll(value, `\`, `\\`)
+		value = strings.ReplaceAll(value, `"`, `\"`)
+		fmt.Fprintf(&sb, "[%s \"%s\"]\n", name, value)
+	}
+	for _, name := range sevenTagRoster {
+		value, ok := g.Tags[name]
+		switch {
+		case name == "Result":
+			value = g.Result
+		case !ok:
+			value = "?"
+		}
+		writeTag(name, value)
+	}
+	var names []string
+	for name := range g.Tags {
+		if !slices.Contains(sevenTagRoster, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		writeTag(name, g.Tags[name])
+	}
+	sb.WriteByte('\n')
+
+	// The move numbers depend on where the game started.
+	ply := 0
+	if b, err := g.Board(); err == nil {
+		ply = b.Ply()
+	}
+	var tokens []string
+	for i, m := range g.Moves {
+		if ply%2 == 0 {
+			tokens = append(tokens, fmt.Sprintf("%d.", ply/2+1))
+		} else if i == 0 {
+			tokens = append(tokens, fmt.Sprintf("%d...", ply/2+1))
+		}
+		tokens = append(tokens, m)
+		ply += 1
+	}
+	tokens = append(tokens, g.Result)
+	var line int
+	for i, tok := range tokens {
+		if i != 0 {
+			if line+1+len(tok) > 79 {
+				sb.WriteByte('\n')
+				line = 0
+			} else {
+				sb.WriteByte(' ')
+				line += 1
+			}
+		}
+		sb.WriteString(tok)
+		line += len(tok)
+	}
+	sb.WriteString("\n\n")
+
+	n, err := io.WriteString(w, sb.String())
+	return int64(n), err
+}
+
+// PGNReader reads games from a PGN database.
+type PGNReader struct {
+	r    *bufio.Reader
+	line int
+}
+
+// NewPGNReader returns a PGNReader reading from r.
+func NewPGNReader(r io.Reader) *PGNReader {
+	return &PGNReader{r: bufio.NewReader(r), line: 1}
+}
+
+// readRune reads a rune, keeping track of line numbers.
+func (pr *PGNReader) readRune() (rune, error) {
+	c, _, err := pr.r.ReadRune()
+	if c == '\n' {
+		pr.line += 1
+	}
+	return c, err
+}
+
+// unreadRune pushes the last rune back onto the reader.
+func (pr *PGNReader) unreadRune(c rune) {
+	if c == '\n' {
+		pr.line -= 1
+	}
+	pr.r.UnreadRune()
+}
+
+// skipUntil discards input up to and including the delimiter.
+func (pr *PGNReader) skipUntil(delim rune) error {
+	for {
+		c, err := pr.readRune()
+		if err != nil {
+			return err
+		}
+		if c == delim {
+			return nil
+		}
+	}
+}
+
+// readTag reads a tag pair, after its opening '['.
+func (pr *PGNReader) readTag() (string, string, error) {
+	var name, value strings.Builder
+	inValue, escaped := false, false
+	for {
+		c, err := pr.readRune()
+		if err != nil {
+			return "", "", err
+		}
+		switch {
+		case inValue && escaped:
+			value.WriteRune(c)
+			escaped = false
+		case inValue && c == '\\':
+			escaped = true
+		case inValue && c == '"':
+			inValue = false
+		case inValue:
+			value.WriteRune(c)
+		case c == '"':
+			inValue = true
+		case c == ']':
+			return strings.TrimSpace(name.String()), value.String(), nil
+		case !unicode.IsSpace(c):
+			name.WriteRune(c)
+		}
+	}
+}
+
+// readSymbol reads a movetext token.
+func (pr *PGNReader) readSymbol(first rune) (string, error) {
+	var sb strings.Builder
+	sb.WriteRune(first)
+	for {
+		c, err := pr.readRune()
+		if errors.Is(err, io.EOF) {
+			return sb.String(), nil
+		} else if err != nil {
+			return "", err
+		}
+		if unicode.IsSpace(c) || strings.ContainsRune("[]{}();", c) {
+			pr.unreadRune(c)
+			return sb.String(), nil
+		}
+		sb.WriteRune(c)
+	}
+}
+
+// Next returns the next game in the database, or io.EOF if there are no more.
+func (pr *PGNReader) Next() (*Game, error) {
+	g := &Game{Tags: make(map[string]string)}
+	inMoves, depth := false, 0
+	errorf := func(format string, args ...any) error {
+		return fmt.Errorf("line %d: %s", pr.line, fmt.Sprintf(format, args...))
+	}
+
+	for {
+		c, err := pr.readRune()
+		if errors.Is(err, io.EOF) {
+			// Tolerate a missing termination marker at the end of the file.
+			if len(g.Tags) == 0 && len(g.Moves) == 0 {
+				return nil, io.EOF
+			}
+			g.Result = "*"
+			return g, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case unicode.IsSpace(c):
+		case c == '[':
+			// A tag after movetext is the start of the next game.
+			if inMoves {
+				pr.unreadRune(c)
+				g.Result = "*"
+				return g, nil
+			}
+			name, value, err := pr.readTag()
+			if err != nil {
+				return nil, errorf("error reading tag: %v", err)
+			}
+			g.Tags[name] = value
+		case c == '{':
+			if err := pr.skipUntil('}'); err != nil {
+				return nil, errorf("unterminated comment")
+			}
+		case c == ';', c == '%':
+			if err := pr.skipUntil('\n'); err != nil && !errors.Is(err, io.EOF) {
+				return nil, err
+			}
+		case c == '(':
+			depth += 1
+		case c == ')':
+			if depth == 0 {
+				return nil, errorf("unbalanced variation")
+			}
+			depth -= 1
+		default:
+			inMoves = true
+			tok, err := pr.readSymbol(c)
+			if err != nil {
+				return nil, err
+			}
+			if depth != 0 || strings.HasPrefix(tok, "$") {
+				continue
+			}
+			if isResult(tok) {
+				g.Result = tok
+				return g, nil
+			}
+
+			// Strip move numbers, which might be attached to the move (eg, "1.e4").
+			if unicode.IsDigit(rune(tok[0])) {
+				if idx := strings.LastIndexByte(tok, '.'); idx >= 0 {
+					tok = tok[idx+1:]
+				}
+			}
+			if tok = strings.TrimRight(tok, "!?"); len(tok) != 0 {
+				g.Moves = append(g.Moves, tok)
+			}
+		}
+	}
+}
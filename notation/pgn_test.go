@@ -0,0 +1,99 @@
+package notation
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testPGN = `[Event "Test"]
+[White "A \"quoted\" name"]
+[Black "B"]
+[Result "1-0"]
+
+1. e4 e5 2. Nf3 {a comment} Nc6 (2... d6 3. d4) 3. Bb5 $1 a6?! ; rest of line
+4.Ba4 1-0
+
+[Event "Second"]
+[Result "1/2-1/2"]
+
+1. d4 d5 1/2-1/2
+
+1. c4 *
+`
+
+func TestPGNReader(t *testing.T) {
+	tests := []Game{
+		{
+			Tags:   map[string]string{"Event": "Test", "White": `A "quoted" name`, "Black": "B", "Result": "1-0"},
+			Moves:  []string{"e4", "e5", "Nf3", "Nc6", "Bb5", "a6", "Ba4"},
+			Result: "1-0",
+		},
+		{
+			Tags:   map[string]string{"Event": "Second", "Result": "1/2-1/2"},
+			Moves:  []string{"d4", "d5"},
+			Result: "1/2-1/2",
+		},
+		{
+			Tags:   map[string]string{},
+			Moves:  []string{"c4"},
+			Result: "*",
+		},
+	}
+
+	pr := NewPGNReader(strings.NewReader(testPGN))
+	for i, test := range tests {
+		g, err := pr.Next()
+		if err != nil {
+			t.Fatalf("[%d] Next() = %v", i, err)
+		}
+		if !reflect.DeepEqual(*g, test) {
+			t.Errorf("[%d] Next() = %+v, expected %+v", i, *g, test)
+		}
+	}
+	if _, err := pr.Next(); !errors.Is(err, io.EOF) {
+		t.Errorf("Next() = %v, expected EOF", err)
+	}
+}
+
+func TestPGNWriteTo(t *testing.T) {
+	g := &Game{
+		Tags: map[string]string{
+			"Event": "Test", "White": `A "quoted" name`, "Black": "B",
+			"FEN": "rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq - 0 1", "SetUp": "1",
+		},
+		Moves:  []string{"e5", "Nf3", "Nc6"},
+		Result: "1/2-1/2",
+	}
+	expected := `[Event "Test"]
+[Site "?"]
+[Date "?"]
+[Round "?"]
+[White "A \"quoted\" name"]
+[Black "B"]
+[Result "1/2-1/2"]
+[FEN "rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq - 0 1"]
+[SetUp "1"]
+
+1... e5 2. Nf3 Nc6 1/2-1/2
+
+`
+	var sb strings.Builder
+	if _, err := g.WriteTo(&sb); err != nil {
+		t.Fatalf("WriteTo() = %v", err)
+	}
+	if sb.String() != expected {
+		t.Errorf("WriteTo() = %q, expected %q", sb.String(), expected)
+	}
+
+	// And it should read back in.
+	rg, err := NewPGNReader(strings.NewReader(sb.String())).Next()
+	if err != nil {
+		t.Fatalf("Next() = %v", err)
+	}
+	if !reflect.DeepEqual(rg.Moves, g.Moves) || rg.Result != g.Result || rg.Tags["White"] != g.Tags["White"] {
+		t.Errorf("Next() = %+v, expected %+v", *rg, *g)
+	}
+}
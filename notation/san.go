@@ -0,0 +1,183 @@
+// Package notation reads and writes chess notation: Standard Algebraic
+// Notation for moves, PGN for games, and EPD for test positions. FEN, which
+// boards are built from, is in package chess.
+package notation
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"chess"
+)
+
+// ParseSAN parses a move in Standard Algebraic Notation (eg, "Nf3", "exd5",
+// "O-O", "e8=Q+") and returns the matching legal move.
+func ParseSAN(b *chess.Board, s string) (chess.Move, error) {
+	str := strings.TrimRight(s, "+#!?")
+	if len(str) == 0 {
+		return chess.Move{}, fmt.Errorf("invalid move: %q", s)
+	}
+
+	// Castling is special-cased.
+	switch str {
+	case "O-O", "0-0":
+		return findSANMove(b, s, func(m *chess.Move) bool { return m.IsKingsideCastle() })
+	case "O-O-O", "0-0-0":
+		return findSANMove(b, s, func(m *chess.Move) bool { return m.IsQueensideCastle() })
+	}
+
+	// Strip the promotion, which can be written either as "e8=Q" or "e8Q".
+	var promotion chess.Piece
+	if idx := strings.IndexByte(str, '='); idx >= 0 {
+		if idx != len(str)-2 {
+			return chess.Move{}, fmt.Errorf("invalid promotion: %q", s)
+		}
+		promotion = sanPiece(unicode.ToUpper(rune(str[idx+1])))
+		str = str[:idx]
+	} else if l := len(str); l > 2 && unicode.IsDigit(rune(str[l-2])) {
+		promotion = sanPiece(rune(str[l-1]))
+		str = str[:l-1]
+	}
+	if promotion == chess.Pawn || promotion == chess.King {
+		return chess.Move{}, fmt.Errorf("invalid promotion: %q", s)
+	}
+
+	// Get the destination.
+	if len(str) < 2 {
+		return chess.Move{}, fmt.Errorf("invalid move: %q", s)
+	}
+	to, err := chess.CoordFromString(str[len(str)-2:])
+	if err != nil || to == chess.InvalidCoord {
+		return chess.Move{}, fmt.Errorf("invalid destination: %q", s)
+	}
+	str = strings.TrimSuffix(str[:len(str)-2], "x")
+
+	// And the piece, along with any disambiguation.
+	piece := chess.Piece(chess.Pawn)
+	if len(str) != 0 && unicode.IsUpper(rune(str[0])) {
+		if piece = sanPiece(rune(str[0])); piece == chess.Empty {
+			return chess.Move{}, fmt.Errorf("invalid piece: %q", s)
+		}
+		str = str[1:]
+	}
+	file, rank := -1, -1
+	for _, c := range str {
+		switch {
+		case c >= 'a' && c <= 'h':
+			file = int(c - 'a')
+		case c >= '1' && c <= '8':
+			rank = int(c - '1')
+		default:
+			return chess.Move{}, fmt.Errorf("invalid move: %q", s)
+		}
+	}
+
+	return findSANMove(b, s, func(m *chess.Move) bool {
+		if m.To() != to || m.Piece().Colorless() != piece || m.IsCastle() {
+			return false
+		}
+		if file != -1 && m.From().X() != file || rank != -1 && m.From().Y() != rank {
+			return false
+		}
+		if m.IsPromotion() {
+			return m.Promotion().Colorless() == promotion
+		}
+		return promotion == chess.Empty
+	})
+}
+
+// SAN returns a legal move in Standard Algebraic Notation, disambiguating it
+// from the board's other legal moves, and marking checks and mates.
+func SAN(b *chess.Board, m chess.Move) string {
+	var sb strings.Builder
+	switch {
+	case m.IsKingsideCastle():
+		sb.WriteString("O-O")
+	case m.IsQueensideCastle():
+		sb.WriteString("O-O-O")
+	case m.Piece().Colorless() == chess.Pawn:
+		if m.IsCapture() {
+			sb.WriteByte(byte('a' + m.From().X()))
+			sb.WriteByte('x')
+		}
+		sb.WriteString(m.To().String())
+		if m.IsPromotion() {
+			sb.WriteString("=" + strings.ToUpper(m.Promotion().NoteString()))
+		}
+	default:
+		sb.WriteString(strings.ToUpper(m.Piece().NoteString()))
+		var sameFile, sameRank, ambiguous bool
+		for _, o := range b.PossibleMoves(nil) {
+			if o.Piece() != m.Piece() || o.To() != m.To() || o.From() == m.From() {
+				continue
+			}
+			ambiguous = true
+			sameFile = sameFile || o.From().X() == m.From().X()
+			sameRank = sameRank || o.From().Y() == m.From().Y()
+		}
+		if ambiguous {
+			from := m.From().String()
+			switch {
+			case !sameFile:
+				sb.WriteByte(from[0])
+			case !sameRank:
+				sb.WriteByte(from[1])
+			default:
+				sb.WriteString(from)
+			}
+		}
+		if m.IsCapture() {
+			sb.WriteByte('x')
+		}
+		sb.WriteString(m.To().String())
+	}
+
+	b.MakeMove(m)
+	if b.IsCheck() {
+		if len(b.PossibleMoves(nil)) == 0 {
+			sb.WriteByte('#')
+		} else {
+			sb.WriteByte('+')
+		}
+	}
+	b.UnmakeMove()
+	return sb.String()
+}
+
+// findSANMove returns the single legal move matching a filter.
+func findSANMove(b *chess.Board, s string, match func(*chess.Move) bool) (chess.Move, error) {
+	var found []chess.Move
+	for _, m := range b.PossibleMoves(nil) {
+		if match(&m) {
+			found = append(found, m)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return chess.Move{}, fmt.Errorf("move wasn't legal: %q", s)
+	case 1:
+		return found[0], nil
+	}
+	return chess.Move{}, fmt.Errorf("ambiguous move: %q", s)
+}
+
+// sanPiece returns the colorless piece for a SAN piece letter, or Empty if the
+// letter isn't a piece.
+func sanPiece(r rune) chess.Piece {
+	switch r {
+	case 'P':
+		return chess.Pawn
+	case 'N':
+		return chess.Knight
+	case 'B':
+		return chess.Bishop
+	case 'R':
+		return chess.Rook
+	case 'Q':
+		return chess.Queen
+	case 'K':
+		return chess.King
+	}
+	return chess.Empty
+}
@@ -0,0 +1,88 @@
+package notation
+
+import (
+	"testing"
+
+	"chess"
+)
+
+func TestParseSAN(t *testing.T) {
+	tests := []struct {
+		fen   string
+		san   string
+		move  string
+		isErr bool
+	}{
+		{chess.StartingFEN, "e4", "e2e4", false},
+		{chess.StartingFEN, "Nf3", "g1f3", false},
+		{chess.StartingFEN, "Nf3!?", "g1f3", false},
+		{chess.StartingFEN, "e5", "", true},
+		{chess.StartingFEN, "Ke2", "", true},
+		{"r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1", "O-O", "e1g1", false},
+		{"r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1", "O-O-O", "e1c1", false},
+		{"r3k2r/8/8/8/8/8/8/R3K2R b KQkq - 0 1", "0-0+", "e8g8", false},
+		{"k7/8/8/8/8/8/4K3/R6R w - - 0 1", "Rd1", "", true}, // ambiguous
+		{"k7/8/8/8/8/8/4K3/R6R w - - 0 1", "Rad1", "a1d1", false},
+		{"k7/8/8/8/8/8/4K3/R6R w - - 0 1", "Rhd1", "h1d1", false},
+		{"k7/8/8/8/8/8/R7/R3K3 w - - 0 1", "R1a1", "", true}, // a1 is occupied
+		{"k7/8/8/8/8/8/R7/R3K3 w - - 0 1", "R2b2", "a2b2", false},
+		{"k7/8/8/pP6/8/8/8/K7 w - a6 0 1", "bxa6", "b5a6", false},
+		{"k7/4P3/8/8/8/8/8/K7 w - - 0 1", "e8=Q+", "e7e8q", false},
+		{"k7/4P3/8/8/8/8/8/K7 w - - 0 1", "e8N", "e7e8n", false},
+		{"k7/4P3/8/8/8/8/8/K7 w - - 0 1", "e8", "", true},
+		{"k7/4P3/8/8/8/8/8/K7 w - - 0 1", "e8=K", "", true},
+		{"k7/8/8/8/8/8/4p3/K7 b - - 0 1", "e1=q", "e2e1q", false},
+	}
+
+	for i, test := range tests {
+		b, err := chess.FromFEN(test.fen)
+		if err != nil {
+			t.Fatalf("[%d] error creating board: %v", i, err)
+		}
+		m, err := ParseSAN(b, test.san)
+		if (err != nil) != test.isErr {
+			t.Errorf("[%d] ParseSAN(%q) = %v, expected error = %t", i, test.san, err, test.isErr)
+			continue
+		}
+		if err == nil && m.UCIString() != test.move {
+			t.Errorf("[%d] ParseSAN(%q) = %v, expected %v", i, test.san, m, test.move)
+		}
+	}
+}
+
+func TestSAN(t *testing.T) {
+	tests := []struct {
+		fen  string
+		move string
+		san  string
+	}{
+		{chess.StartingFEN, "e2e4", "e4"},
+		{chess.StartingFEN, "g1f3", "Nf3"},
+		{"r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1", "e1g1", "O-O"},
+		{"r3k2r/8/8/8/8/8/8/R3K2R b KQkq - 0 1", "e8c8", "O-O-O"},
+		{"k7/8/8/8/8/8/4K3/R6R w - - 0 1", "a1d1", "Rad1"},
+		{"7k/8/8/8/8/R7/8/R3K3 w - - 0 1", "a1a2", "R1a2"},
+		{"k7/8/8/8/8/8/R7/R3K3 w - - 0 1", "a2b2", "Rb2#"},
+		{"8/7k/8/8/Q7/8/8/Q2Q2K1 w - - 0 1", "a1d4", "Qa1d4"},
+		{"k7/8/8/pP6/8/8/8/K7 w - a6 0 1", "b5a6", "bxa6"},
+		{"k7/4P3/8/8/8/8/8/K7 w - - 0 1", "e7e8q", "e8=Q+"},
+		{"6k1/5ppp/8/8/8/8/8/R5K1 w - - 0 1", "a1a8", "Ra8#"},
+	}
+
+	for i, test := range tests {
+		b, err := chess.FromFEN(test.fen)
+		if err != nil {
+			t.Fatalf("[%d] error creating board: %v", i, err)
+		}
+		m, err := ParseSAN(b, test.san)
+		if err != nil {
+			t.Fatalf("[%d] ParseSAN(%q) = %v", i, test.san, err)
+		}
+		if m.UCIString() != test.move {
+			t.Errorf("[%d] ParseSAN(%q) = %v, expected %v", i, test.san, m.UCIString(), test.move)
+		}
+		if san := SAN(b, m); san != test.san {
+			t.Errorf("[%d] SAN(%v) = %q, expected %q", i, test.move, san, test.san)
+		}
+	}
+}
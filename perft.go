@@ -0,0 +1,281 @@
+package chess
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+type PerftVerbosity int
+
+const (
+	Quiet PerftVerbosity = iota
+	Verbose
+)
+
+// perftHashMB is the size of the hash table used by Perft.
+const perftHashMB = 16
+
+// PerftTable is a fixed-size hash table of perft counts. It's safe for
+// concurrent use, without locks: each entry's key is stored xored with its
+// data, so a torn read or write just fails to match.
+type PerftTable struct {
+	entries []perftEntry
+	mask    uint64
+}
+
+type perftEntry struct {
+	key  atomic.Uint64 // The position's hash, xored with data.
+	data atomic.Uint64 // The count, shifted left 8, and the depth.
+}
+
+// NewPerftTable creates a PerftTable using at most sizeMB megabytes, or nil if
+// sizeMB isn't positive.
+func NewPerftTable(sizeMB int) *PerftTable {
+	if sizeMB <= 0 {
+		return nil
+	}
+	n := uint64(1)
+	for n*2*uint64(unsafe.Sizeof(perftEntry{})) <= uint64(sizeMB)<<20 {
+		n *= 2
+	}
+	return &PerftTable{entries: make([]perftEntry, n), mask: n - 1}
+}
+
+// lookup returns the count for a position at depth, if it's in the table.
+func (t *PerftTable) lookup(h Hash, depth int) (uint64, bool) {
+	e := &t.entries[uint64(h)&t.mask]
+	data := e.data.Load()
+	if e.key.Load()^data != uint64(h) || int(data&0xff) != depth {
+		return 0, false
+	}
+	return data >> 8, true
+}
+
+// store saves the count for a position at depth, replacing whatever was in
+// its entry.
+func (t *PerftTable) store(h Hash, depth int, cnt uint64) {
+	e := &t.entries[uint64(h)&t.mask]
+	data := cnt<<8 | uint64(depth)
+	e.key.Store(uint64(h) ^ data)
+	e.data.Store(data)
+}
+
+// perfter counts the leaf nodes below a board's position.
+type perfter struct {
+	b     *Board
+	moves [][]Move // Move lists for each depth, to save allocating them.
+	tt    *PerftTable
+}
+
+func newPerfter(b *Board, depth int, tt *PerftTable) *perfter {
+	return &perfter{b: b, moves: make([][]Move, depth+1), tt: tt}
+}
+
+// count returns the number of leaf nodes depth moves from the position.
+func (p *perfter) count(depth int) uint64 {
+	b := p.b
+	if p.tt != nil {
+		if cnt, ok := p.tt.lookup(b.state.hash, depth); ok {
+			return cnt
+		}
+	}
+	moves := b.PossibleMoves(p.moves[depth][:0])
+	p.moves[depth] = moves
+	total := uint64(len(moves))
+	if depth > 1 {
+		total = 0
+		for _, m := range moves {
+			b.MakeMove(m)
+			total += p.count(depth - 1)
+			b.UnmakeMove()
+		}
+	}
+	if p.tt != nil {
+		p.tt.store(b.state.hash, depth, total)
+	}
+	return total
+}
+
+// Perft calculates the number of possible moves at a given depth. It's quite
+// helpful debugging the move generation. Optionally, Perft will also print the
+// number of reachable moves for each valid move in the given board state.
+func (b *Board) Perft(origDepth int, verbosity PerftVerbosity) uint64 {
+	var visit func(Move, uint64)
+	if verbosity == Verbose {
+		visit = func(m Move, cnt uint64) { fmt.Printf("%v: %d\n", m, cnt) }
+	}
+	return b.divide(origDepth, 1, 0, visit)
+}
+
+// Divide returns the perft count at a given depth below each of the legal
+// moves, keyed by the move in UCI notation.
+func (b *Board) Divide(depth int) map[string]uint64 {
+	counts := make(map[string]uint64)
+	b.divide(depth, 1, 0, func(m Move, cnt uint64) { counts[m.UCIString()] = cnt })
+	return counts
+}
+
+// PerftParallel calculates perft like Perft, but splits the root moves across
+// threads goroutines (GOMAXPROCS if threads isn't positive), each searching a
+// copy of the board, and sharing a hash table of hashMB megabytes (a default
+// size if 0, and none if negative). If visit
+// isn't nil, it's called with each root move's count, in the order of
+// PossibleMoves.
+func (b *Board) PerftParallel(depth, threads, hashMB int, visit func(Move, uint64)) uint64 {
+	if threads <= 0 {
+		threads = runtime.GOMAXPROCS(0)
+	}
+	return b.divide(depth, threads, hashMB, visit)
+}
+
+// divide calculates perft at a given depth with threads goroutines, calling
+// visit, if it isn't nil, with the count for each legal move. If hashMB is 0,
+// a table of perftHashMB is used for depths that benefit from one.
+func (b *Board) divide(depth, threads, hashMB int, visit func(Move, uint64)) uint64 {
+	if depth <= 0 {
+		return 0
+	}
+	if hashMB == 0 && depth > 3 {
+		hashMB = perftHashMB
+	}
+	tt := NewPerftTable(hashMB)
+
+	moves := b.PossibleMoves(nil)
+	counts := make([]uint64, len(moves))
+	if depth == 1 {
+		for i := range counts {
+			counts[i] = 1
+		}
+	} else if threads <= 1 {
+		p := newPerfter(b, depth, tt)
+		for i, m := range moves {
+			b.MakeMove(m)
+			counts[i] = p.count(depth - 1)
+			b.UnmakeMove()
+		}
+	} else {
+		// Hand out the root moves to the goroutines as they finish.
+		var next atomic.Int64
+		var wg sync.WaitGroup
+		for i := 0; i < min(threads, len(moves)); i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				p := newPerfter(b.Copy(), depth, tt)
+				for {
+					i := int(next.Add(1) - 1)
+					if i >= len(moves) {
+						return
+					}
+					p.b.MakeMove(moves[i])
+					counts[i] = p.count(depth - 1)
+					p.b.UnmakeMove()
+				}
+			}()
+		}
+		wg.Wait()
+	}
+
+	var total uint64
+	for i, m := range moves {
+		if visit != nil {
+			visit(m, counts[i])
+		}
+		total += counts[i]
+	}
+	return total
+}
+
+// PerftStats are the leaf nodes perft finds, broken down by the moves that
+// reach them, as in the tables at
+// https://www.chessprogramming.org/Perft_Results.
+type PerftStats struct {
+	Nodes            uint64
+	Captures         uint64 // Including en passant.
+	EnPassant        uint64
+	Castles          uint64
+	Promotions       uint64
+	Checks           uint64 // Including discovered and double checks.
+	DiscoveredChecks uint64 // Single checks by a piece other than the one that moved.
+	DoubleChecks     uint64
+	Checkmates       uint64
+}
+
+// checkers returns the pieces giving check to the side to move.
+func (b *Board) checkers() Bit {
+	v, king := b.state.wOcc, b.state.bkLoc
+	if b.state.turn == White {
+		v, king = b.state.bOcc, b.state.wkLoc
+	}
+	bit := Bit(1 << king.Idx())
+	totOcc := b.state.wOcc | b.state.bOcc
+	var checkers Bit
+	for v != 0 {
+		from := v.NextCoord()
+		if b.at(from).Attacks(from, totOcc)&bit != 0 {
+			checkers.Set(from.Idx())
+		}
+	}
+	return checkers
+}
+
+// PerftStats calculates perft at a given depth, with the breakdown of the
+// leaf nodes. Unlike Perft, it doesn't use a hash table.
+func (b *Board) PerftStats(depth int) PerftStats {
+	var s PerftStats
+	if depth <= 0 {
+		return s
+	}
+	moveQueue := make([][]Move, depth+1)
+	var perft func(int)
+	perft = func(d int) {
+		moves := b.PossibleMoves(moveQueue[d][:0])
+		moveQueue[d] = moves
+		for _, m := range moves {
+			if d > 1 {
+				b.MakeMove(m)
+				perft(d - 1)
+				b.UnmakeMove()
+				continue
+			}
+
+			s.Nodes++
+			if m.isCapture {
+				s.Captures++
+			}
+			if m.isEnPassant {
+				s.EnPassant++
+			}
+			if m.IsCastle() {
+				s.Castles++
+			}
+			if m.IsPromotion() {
+				s.Promotions++
+			}
+
+			b.MakeMove(m)
+			if checkers := b.checkers(); checkers != 0 {
+				s.Checks++
+				// The moved piece is the castling rook, for castles.
+				moved := Bit(1 << m.to.Idx())
+				if m.IsCastle() {
+					moved = 1 << CoordFromXY((m.from.X()+m.to.X())/2, m.to.Y()).Idx()
+				}
+				if checkers.CountOnes() > 1 {
+					s.DoubleChecks++
+				} else if checkers&^moved != 0 {
+					s.DiscoveredChecks++
+				}
+				if len(b.PossibleMoves(moveQueue[0][:0])) == 0 {
+					s.Checkmates++
+				}
+			}
+			b.UnmakeMove()
+		}
+	}
+	perft(depth)
+	return s
+}
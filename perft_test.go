@@ -0,0 +1,171 @@
+package chess
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestPerftTable(t *testing.T) {
+	if tt := NewPerftTable(0); tt != nil {
+		t.Errorf("NewPerftTable(0) = %v, expected nil", tt)
+	}
+	tt := NewPerftTable(1)
+	if n := len(tt.entries); n != 1<<16 {
+		t.Errorf("NewPerftTable(1) has %d entries, expected %d", n, 1<<16)
+	}
+	if tt := NewPerftTable(3); len(tt.entries) != 1<<17 {
+		t.Errorf("NewPerftTable(3) has %d entries, expected %d", len(tt.entries), 1<<17)
+	}
+
+	h := Hash(0x123456789abcdef)
+	if _, ok := tt.lookup(h, 3); ok {
+		t.Errorf("lookup() found an entry in an empty table")
+	}
+	tt.store(h, 3, 8902)
+	if v, ok := tt.lookup(h, 3); !ok || v != 8902 {
+		t.Errorf("lookup() = %d, %v, expected 8902, true", v, ok)
+	}
+	if _, ok := tt.lookup(h, 4); ok {
+		t.Errorf("lookup() found an entry at the wrong depth")
+	}
+
+	// Positions that share an entry replace each other.
+	other := h + Hash(len(tt.entries))
+	if _, ok := tt.lookup(other, 3); ok {
+		t.Errorf("lookup() found an entry for a different position")
+	}
+	tt.store(other, 3, 20)
+	if _, ok := tt.lookup(h, 3); ok {
+		t.Errorf("lookup() found a replaced entry")
+	}
+	if v, ok := tt.lookup(other, 3); !ok || v != 20 {
+		t.Errorf("lookup() = %d, %v, expected 20, true", v, ok)
+	}
+}
+
+func TestPerftParallel(t *testing.T) {
+	tests := []struct {
+		fen   string
+		depth int
+		nodes uint64
+	}{
+		{StartingFEN, 1, 20},
+		{StartingFEN, 4, 197281},
+		{"r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1", 3, 97862},
+		{"8/2p5/3p4/KP5r/1R3p1k/8/4P1P1/8 w - - 0 1", 4, 43238},
+		{"r3k2r/Pppp1ppp/1b3nbN/nP6/BBP1P3/q4N2/Pp1P2PP/R2Q1RK1 w kq - 0 1", 3, 9467},
+		{"rnbq1k1r/pp1Pbppp/2p5/8/2B5/8/PPP1NnPP/RNBQK2R w KQ - 1 8", 3, 62379},
+	}
+	for i, test := range tests {
+		for _, threads := range []int{1, 4} {
+			for _, hashMB := range []int{-1, 1} {
+				b, err := FromFEN(test.fen)
+				if err != nil {
+					t.Fatalf("[%d] FromFEN() = %v", i, err)
+				}
+				fen := b.FENString()
+				divide := b.Divide(test.depth)
+				visited := make(map[string]uint64)
+				nodes := b.PerftParallel(test.depth, threads, hashMB, func(m Move, cnt uint64) { visited[m.UCIString()] = cnt })
+				if nodes != test.nodes {
+					t.Errorf("[%d] PerftParallel(%d, %d, %d) = %d, expected %d", i, test.depth, threads, hashMB, nodes, test.nodes)
+				}
+				if fmt.Sprint(visited) != fmt.Sprint(divide) {
+					t.Errorf("[%d] PerftParallel(%d, %d, %d) visited %v, expected %v", i, test.depth, threads, hashMB, visited, divide)
+				}
+				if b.FENString() != fen {
+					t.Errorf("[%d] PerftParallel() changed the board to %v", i, b.FENString())
+				}
+			}
+		}
+	}
+}
+
+func TestPerftStats(t *testing.T) {
+	const (
+		kiwipete = "r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1"
+		pos3     = "8/2p5/3p4/KP5r/1R3p1k/8/4P1P1/8 w - - 0 1"
+		pos4     = "r3k2r/Pppp1ppp/1b3nbN/nP6/BBP1P3/q4N2/Pp1P2PP/R2Q1RK1 w kq - 0 1"
+		pos5     = "rnbq1k1r/pp1Pbppp/2p5/8/2B5/8/PPP1NnPP/RNBQK2R w KQ - 1 8"
+		pos6     = "r4rk1/1pp1qppp/p1np1n2/2b1p1B1/2B1P1b1/P1NP1N2/1PP1QPPP/R4RK1 w - - 0 10"
+	)
+	// From https://www.chessprogramming.org/Perft_Results, which only has node
+	// counts for positions 5 and 6.
+	tests := []struct {
+		fen       string
+		depth     int
+		expected  PerftStats
+		nodesOnly bool
+		long      bool
+	}{
+		{StartingFEN, 1, PerftStats{20, 0, 0, 0, 0, 0, 0, 0, 0}, false, false},
+		{StartingFEN, 2, PerftStats{400, 0, 0, 0, 0, 0, 0, 0, 0}, false, false},
+		{StartingFEN, 3, PerftStats{8902, 34, 0, 0, 0, 12, 0, 0, 0}, false, false},
+		{StartingFEN, 4, PerftStats{197281, 1576, 0, 0, 0, 469, 0, 0, 8}, false, false},
+		{StartingFEN, 5, PerftStats{4865609, 82719, 258, 0, 0, 27351, 6, 0, 347}, false, true},
+		{kiwipete, 1, PerftStats{48, 8, 0, 2, 0, 0, 0, 0, 0}, false, false},
+		{kiwipete, 2, PerftStats{2039, 351, 1, 91, 0, 3, 0, 0, 0}, false, false},
+		{kiwipete, 3, PerftStats{97862, 17102, 45, 3162, 0, 993, 0, 0, 1}, false, false},
+		{kiwipete, 4, PerftStats{4085603, 757163, 1929, 128013, 15172, 25523, 42, 6, 43}, false, true},
+		{pos3, 1, PerftStats{14, 1, 0, 0, 0, 2, 0, 0, 0}, false, false},
+		{pos3, 2, PerftStats{191, 14, 0, 0, 0, 10, 0, 0, 0}, false, false},
+		{pos3, 3, PerftStats{2812, 209, 2, 0, 0, 267, 3, 0, 0}, false, false},
+		{pos3, 4, PerftStats{43238, 3348, 123, 0, 0, 1680, 106, 0, 17}, false, false},
+		{pos3, 5, PerftStats{674624, 52051, 1165, 0, 0, 52950, 1292, 3, 0}, false, true},
+		{pos4, 1, PerftStats{6, 0, 0, 0, 0, 0, 0, 0, 0}, false, false},
+		{pos4, 2, PerftStats{264, 87, 0, 6, 48, 10, 0, 0, 0}, false, false},
+		{pos4, 3, PerftStats{9467, 1021, 4, 0, 120, 38, 2, 0, 22}, false, false},
+		{pos4, 4, PerftStats{422333, 131393, 0, 7795, 60032, 15492, 19, 0, 5}, false, true},
+		{pos5, 1, PerftStats{Nodes: 44}, true, false},
+		{pos5, 2, PerftStats{Nodes: 1486}, true, false},
+		{pos5, 3, PerftStats{Nodes: 62379}, true, false},
+		{pos5, 4, PerftStats{Nodes: 2103487}, true, true},
+		{pos6, 1, PerftStats{Nodes: 46}, true, false},
+		{pos6, 2, PerftStats{Nodes: 2079}, true, false},
+		{pos6, 3, PerftStats{Nodes: 89890}, true, false},
+		{pos6, 4, PerftStats{Nodes: 3894594}, true, true},
+	}
+	for i, test := range tests {
+		if test.long && testing.Short() {
+			continue
+		}
+		b, err := FromFEN(test.fen)
+		if err != nil {
+			t.Fatalf("[%d] FromFEN() = %v", i, err)
+		}
+		fen := b.FENString()
+		s := b.PerftStats(test.depth)
+		if test.nodesOnly {
+			if s.Nodes != test.expected.Nodes {
+				t.Errorf("[%d] PerftStats(%d).Nodes = %d, expected %d", i, test.depth, s.Nodes, test.expected.Nodes)
+			}
+		} else if s != test.expected {
+			t.Errorf("[%d] PerftStats(%d) = %+v, expected %+v", i, test.depth, s, test.expected)
+		}
+		if b.FENString() != fen {
+			t.Errorf("[%d] PerftStats() changed the board to %v", i, b.FENString())
+		}
+	}
+}
+
+func TestBoardCopy(t *testing.T) {
+	b := New()
+	b.ApplyMoves([]string{"e2e4", "e7e5"})
+	c := b.Copy()
+	c.ApplyMoves([]string{"g1f3"})
+	c.UnmakeMove()
+	c.UnmakeMove()
+	if b.FENString() != "rnbqkbnr/pppp1ppp/8/4p3/4P3/8/PPPP1PPP/RNBQKBNR w KQkq e6 0 2" {
+		t.Errorf("Copy() shares state with the board: %v", b.FENString())
+	}
+	if c.FENString() != "rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1" {
+		t.Errorf("Copy() = %v, expected the position after e4", c.FENString())
+	}
+}
+
+func BenchmarkPerftParallel(b *testing.B) {
+	board := New()
+	for n := 0; n < b.N; n++ {
+		board.PerftParallel(5, 0, 0, nil)
+	}
+}
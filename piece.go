@@ -1,4 +1,4 @@
-package main
+package chess
 
 import (
 	"fmt"
@@ -18,7 +18,7 @@ const (
 	Black        = 8
 )
 
-//go:generate go run piece.go piece_gen.go dir.go coord.go bit.go
+//go:generate go run ./internal/genrun piece_gen.go piece.go dir.go coord.go bit.go
 
 type Score int16
 
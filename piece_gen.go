@@ -78,18 +78,18 @@ func (p Piece) attackDistance(d Dir) int {
 }
 
 // genMoves generates the set of moves for a piece at a coordinate.
-func genMoves(p Piece, c Coord) (moves []Coord) {
+func genMoves(p Piece, c Coord) (b Bit) {
 	if p.isSlider() {
-		return moves
+		return b
 	}
 	for _, d := range p.moveDir() {
 		pos := c.ApplyDir(d)
 		if !pos.IsValid() {
 			continue
 		}
-		moves = append(moves, pos)
+		b.Set(pos.Idx())
 	}
-	return moves
+	return b
 }
 
 // genAttacks returns a bit for a piece and a location.
@@ -106,31 +106,15 @@ func genAttacks(p Piece, c Coord) (b Bit) {
 
 func gen(w io.Writer) {
 	// Make the moves LUT.
-	movesForPiece := make([][64][]Coord, Black*2)
-	for _, c := range []Piece{White, Black} {
-		for _, p := range []Piece{Pawn, Knight, King} {
-			p |= c
-			for i := 0; i < 64; i++ {
-				coord := CoordFromIdx(i)
-				movesForPiece[p][i] = genMoves(p, coord)
+	fmt.Fprintf(w, "var movesForPiece = [][64]Bit {\n")
+	for i := Piece(0); i < Black*2; i++ {
+		fmt.Fprintf(w, "\t[64]Bit {")
+		if p := i.Colorless(); p == Pawn || p == Knight || p == King {
+			for j := 0; j < 64; j++ {
+				fmt.Fprintf(w, "%s, ", genMoves(i, CoordFromIdx(j)))
 			}
 		}
-	}
-
-	fmt.Fprintf(w, "var movesForPiece = [][64][]Coord {\n")
-	for i := range movesForPiece {
-		fmt.Fprintf(w, "\t[64][]Coord {\n")
-		p := Piece(i).Colorless()
-		if p == Pawn || p == Knight || p == King {
-			for j := range movesForPiece[i] {
-				fmt.Fprintf(w, "\t\t[]Coord {")
-				for k := range movesForPiece[i][j] {
-					fmt.Fprintf(w, " %d,", movesForPiece[i][j][k].Idx())
-				}
-				fmt.Fprintf(w, "\t\t},\n")
-			}
-		}
-		fmt.Fprintf(w, "\t},\n")
+		fmt.Fprintf(w, "},\n")
 	}
 	fmt.Fprintf(w, "}\n\n")
 
@@ -162,17 +146,17 @@ func main() {
 	}
 }
 
-var header = `package main
+var header = `package chess
 // Code generated by go generate. DO NOT EDIT.
 
-// Moves returns a slice of slices of all the squares a piece could possibly move for
-// a Piece at a given Coord.
-func (p Piece) Moves(c Coord, occ Bit) []Coord {
+// Moves returns a Bit of all the squares a piece could possibly move to for
+// a Piece at a given Coord. Queens are handled as a rook and a bishop.
+func (p Piece) Moves(c Coord, occ Bit) Bit {
 	if p.isSlider() {
 		if p.Colorless() == Bishop {
-			return bishopLookup(c, occ)
+			return bishopBit(c, occ)
 		}
-		return rookLookup(c, occ)
+		return rookBit(c, occ)
 	}
 	return movesForPiece[p][c.Idx()]
 }
@@ -1,15 +1,15 @@
-package main
+package chess
 
 // Code generated by go generate. DO NOT EDIT.
 
-// Moves returns a slice of slices of all the squares a piece could possibly move for
-// a Piece at a given Coord.
-func (p Piece) Moves(c Coord, occ Bit) []Coord {
+// Moves returns a Bit of all the squares a piece could possibly move to for
+// a Piece at a given Coord. Queens are handled as a rook and a bishop.
+func (p Piece) Moves(c Coord, occ Bit) Bit {
 	if p.isSlider() {
 		if p.Colorless() == Bishop {
-			return bishopLookup(c, occ)
+			return bishopBit(c, occ)
 		}
-		return rookLookup(c, occ)
+		return rookBit(c, occ)
 	}
 	return movesForPiece[p][c.Idx()]
 }
@@ -36,413 +36,23 @@ func (p Piece) Attacks(c Coord, occ Bit) Bit {
 	panic("unknown piece")
 }
 
-var movesForPiece = [][64][]Coord{
-	[64][]Coord{},
-	[64][]Coord{
-		[]Coord{8, 16, 9},
-		[]Coord{9, 17, 10, 8},
-		[]Coord{10, 18, 11, 9},
-		[]Coord{11, 19, 12, 10},
-		[]Coord{12, 20, 13, 11},
-		[]Coord{13, 21, 14, 12},
-		[]Coord{14, 22, 15, 13},
-		[]Coord{15, 23, 14},
-		[]Coord{16, 24, 17},
-		[]Coord{17, 25, 18, 16},
-		[]Coord{18, 26, 19, 17},
-		[]Coord{19, 27, 20, 18},
-		[]Coord{20, 28, 21, 19},
-		[]Coord{21, 29, 22, 20},
-		[]Coord{22, 30, 23, 21},
-		[]Coord{23, 31, 22},
-		[]Coord{24, 32, 25},
-		[]Coord{25, 33, 26, 24},
-		[]Coord{26, 34, 27, 25},
-		[]Coord{27, 35, 28, 26},
-		[]Coord{28, 36, 29, 27},
-		[]Coord{29, 37, 30, 28},
-		[]Coord{30, 38, 31, 29},
-		[]Coord{31, 39, 30},
-		[]Coord{32, 40, 33},
-		[]Coord{33, 41, 34, 32},
-		[]Coord{34, 42, 35, 33},
-		[]Coord{35, 43, 36, 34},
-		[]Coord{36, 44, 37, 35},
-		[]Coord{37, 45, 38, 36},
-		[]Coord{38, 46, 39, 37},
-		[]Coord{39, 47, 38},
-		[]Coord{40, 48, 41},
-		[]Coord{41, 49, 42, 40},
-		[]Coord{42, 50, 43, 41},
-		[]Coord{43, 51, 44, 42},
-		[]Coord{44, 52, 45, 43},
-		[]Coord{45, 53, 46, 44},
-		[]Coord{46, 54, 47, 45},
-		[]Coord{47, 55, 46},
-		[]Coord{48, 56, 49},
-		[]Coord{49, 57, 50, 48},
-		[]Coord{50, 58, 51, 49},
-		[]Coord{51, 59, 52, 50},
-		[]Coord{52, 60, 53, 51},
-		[]Coord{53, 61, 54, 52},
-		[]Coord{54, 62, 55, 53},
-		[]Coord{55, 63, 54},
-		[]Coord{56, 57},
-		[]Coord{57, 58, 56},
-		[]Coord{58, 59, 57},
-		[]Coord{59, 60, 58},
-		[]Coord{60, 61, 59},
-		[]Coord{61, 62, 60},
-		[]Coord{62, 63, 61},
-		[]Coord{63, 62},
-		[]Coord{},
-		[]Coord{},
-		[]Coord{},
-		[]Coord{},
-		[]Coord{},
-		[]Coord{},
-		[]Coord{},
-		[]Coord{},
-	},
-	[64][]Coord{
-		[]Coord{17, 10},
-		[]Coord{18, 11, 16},
-		[]Coord{19, 12, 8, 17},
-		[]Coord{20, 13, 9, 18},
-		[]Coord{21, 14, 10, 19},
-		[]Coord{22, 15, 11, 20},
-		[]Coord{23, 12, 21},
-		[]Coord{13, 22},
-		[]Coord{25, 18, 2},
-		[]Coord{26, 19, 3, 24},
-		[]Coord{27, 20, 4, 0, 16, 25},
-		[]Coord{28, 21, 5, 1, 17, 26},
-		[]Coord{29, 22, 6, 2, 18, 27},
-		[]Coord{30, 23, 7, 3, 19, 28},
-		[]Coord{31, 4, 20, 29},
-		[]Coord{5, 21, 30},
-		[]Coord{33, 26, 10, 1},
-		[]Coord{34, 27, 11, 2, 0, 32},
-		[]Coord{35, 28, 12, 3, 1, 8, 24, 33},
-		[]Coord{36, 29, 13, 4, 2, 9, 25, 34},
-		[]Coord{37, 30, 14, 5, 3, 10, 26, 35},
-		[]Coord{38, 31, 15, 6, 4, 11, 27, 36},
-		[]Coord{39, 7, 5, 12, 28, 37},
-		[]Coord{6, 13, 29, 38},
-		[]Coord{41, 34, 18, 9},
-		[]Coord{42, 35, 19, 10, 8, 40},
-		[]Coord{43, 36, 20, 11, 9, 16, 32, 41},
-		[]Coord{44, 37, 21, 12, 10, 17, 33, 42},
-		[]Coord{45, 38, 22, 13, 11, 18, 34, 43},
-		[]Coord{46, 39, 23, 14, 12, 19, 35, 44},
-		[]Coord{47, 15, 13, 20, 36, 45},
-		[]Coord{14, 21, 37, 46},
-		[]Coord{49, 42, 26, 17},
-		[]Coord{50, 43, 27, 18, 16, 48},
-		[]Coord{51, 44, 28, 19, 17, 24, 40, 49},
-		[]Coord{52, 45, 29, 20, 18, 25, 41, 50},
-		[]Coord{53, 46, 30, 21, 19, 26, 42, 51},
-		[]Coord{54, 47, 31, 22, 20, 27, 43, 52},
-		[]Coord{55, 23, 21, 28, 44, 53},
-		[]Coord{22, 29, 45, 54},
-		[]Coord{57, 50, 34, 25},
-		[]Coord{58, 51, 35, 26, 24, 56},
-		[]Coord{59, 52, 36, 27, 25, 32, 48, 57},
-		[]Coord{60, 53, 37, 28, 26, 33, 49, 58},
-		[]Coord{61, 54, 38, 29, 27, 34, 50, 59},
-		[]Coord{62, 55, 39, 30, 28, 35, 51, 60},
-		[]Coord{63, 31, 29, 36, 52, 61},
-		[]Coord{30, 37, 53, 62},
-		[]Coord{58, 42, 33},
-		[]Coord{59, 43, 34, 32},
-		[]Coord{60, 44, 35, 33, 40, 56},
-		[]Coord{61, 45, 36, 34, 41, 57},
-		[]Coord{62, 46, 37, 35, 42, 58},
-		[]Coord{63, 47, 38, 36, 43, 59},
-		[]Coord{39, 37, 44, 60},
-		[]Coord{38, 45, 61},
-		[]Coord{50, 41},
-		[]Coord{51, 42, 40},
-		[]Coord{52, 43, 41, 48},
-		[]Coord{53, 44, 42, 49},
-		[]Coord{54, 45, 43, 50},
-		[]Coord{55, 46, 44, 51},
-		[]Coord{47, 45, 52},
-		[]Coord{46, 53},
-	},
-	[64][]Coord{},
-	[64][]Coord{},
-	[64][]Coord{},
-	[64][]Coord{
-		[]Coord{8, 9, 1},
-		[]Coord{9, 10, 2, 0, 8},
-		[]Coord{10, 11, 3, 1, 9},
-		[]Coord{11, 12, 4, 2, 10},
-		[]Coord{12, 13, 5, 6, 3, 2, 11},
-		[]Coord{13, 14, 6, 4, 12},
-		[]Coord{14, 15, 7, 5, 13},
-		[]Coord{15, 6, 14},
-		[]Coord{16, 17, 9, 1, 0},
-		[]Coord{17, 18, 10, 2, 1, 0, 8, 16},
-		[]Coord{18, 19, 11, 3, 2, 1, 9, 17},
-		[]Coord{19, 20, 12, 4, 3, 2, 10, 18},
-		[]Coord{20, 21, 13, 14, 5, 4, 3, 11, 10, 19},
-		[]Coord{21, 22, 14, 6, 5, 4, 12, 20},
-		[]Coord{22, 23, 15, 7, 6, 5, 13, 21},
-		[]Coord{23, 7, 6, 14, 22},
-		[]Coord{24, 25, 17, 9, 8},
-		[]Coord{25, 26, 18, 10, 9, 8, 16, 24},
-		[]Coord{26, 27, 19, 11, 10, 9, 17, 25},
-		[]Coord{27, 28, 20, 12, 11, 10, 18, 26},
-		[]Coord{28, 29, 21, 22, 13, 12, 11, 19, 18, 27},
-		[]Coord{29, 30, 22, 14, 13, 12, 20, 28},
-		[]Coord{30, 31, 23, 15, 14, 13, 21, 29},
-		[]Coord{31, 15, 14, 22, 30},
-		[]Coord{32, 33, 25, 17, 16},
-		[]Coord{33, 34, 26, 18, 17, 16, 24, 32},
-		[]Coord{34, 35, 27, 19, 18, 17, 25, 33},
-		[]Coord{35, 36, 28, 20, 19, 18, 26, 34},
-		[]Coord{36, 37, 29, 30, 21, 20, 19, 27, 26, 35},
-		[]Coord{37, 38, 30, 22, 21, 20, 28, 36},
-		[]Coord{38, 39, 31, 23, 22, 21, 29, 37},
-		[]Coord{39, 23, 22, 30, 38},
-		[]Coord{40, 41, 33, 25, 24},
-		[]Coord{41, 42, 34, 26, 25, 24, 32, 40},
-		[]Coord{42, 43, 35, 27, 26, 25, 33, 41},
-		[]Coord{43, 44, 36, 28, 27, 26, 34, 42},
-		[]Coord{44, 45, 37, 38, 29, 28, 27, 35, 34, 43},
-		[]Coord{45, 46, 38, 30, 29, 28, 36, 44},
-		[]Coord{46, 47, 39, 31, 30, 29, 37, 45},
-		[]Coord{47, 31, 30, 38, 46},
-		[]Coord{48, 49, 41, 33, 32},
-		[]Coord{49, 50, 42, 34, 33, 32, 40, 48},
-		[]Coord{50, 51, 43, 35, 34, 33, 41, 49},
-		[]Coord{51, 52, 44, 36, 35, 34, 42, 50},
-		[]Coord{52, 53, 45, 46, 37, 36, 35, 43, 42, 51},
-		[]Coord{53, 54, 46, 38, 37, 36, 44, 52},
-		[]Coord{54, 55, 47, 39, 38, 37, 45, 53},
-		[]Coord{55, 39, 38, 46, 54},
-		[]Coord{56, 57, 49, 41, 40},
-		[]Coord{57, 58, 50, 42, 41, 40, 48, 56},
-		[]Coord{58, 59, 51, 43, 42, 41, 49, 57},
-		[]Coord{59, 60, 52, 44, 43, 42, 50, 58},
-		[]Coord{60, 61, 53, 54, 45, 44, 43, 51, 50, 59},
-		[]Coord{61, 62, 54, 46, 45, 44, 52, 60},
-		[]Coord{62, 63, 55, 47, 46, 45, 53, 61},
-		[]Coord{63, 47, 46, 54, 62},
-		[]Coord{57, 49, 48},
-		[]Coord{58, 50, 49, 48, 56},
-		[]Coord{59, 51, 50, 49, 57},
-		[]Coord{60, 52, 51, 50, 58},
-		[]Coord{61, 62, 53, 52, 51, 59, 58},
-		[]Coord{62, 54, 53, 52, 60},
-		[]Coord{63, 55, 54, 53, 61},
-		[]Coord{55, 54, 62},
-	},
-	[64][]Coord{},
-	[64][]Coord{},
-	[64][]Coord{
-		[]Coord{},
-		[]Coord{},
-		[]Coord{},
-		[]Coord{},
-		[]Coord{},
-		[]Coord{},
-		[]Coord{},
-		[]Coord{},
-		[]Coord{0, 1},
-		[]Coord{1, 2, 0},
-		[]Coord{2, 3, 1},
-		[]Coord{3, 4, 2},
-		[]Coord{4, 5, 3},
-		[]Coord{5, 6, 4},
-		[]Coord{6, 7, 5},
-		[]Coord{7, 6},
-		[]Coord{8, 0, 9},
-		[]Coord{9, 1, 10, 8},
-		[]Coord{10, 2, 11, 9},
-		[]Coord{11, 3, 12, 10},
-		[]Coord{12, 4, 13, 11},
-		[]Coord{13, 5, 14, 12},
-		[]Coord{14, 6, 15, 13},
-		[]Coord{15, 7, 14},
-		[]Coord{16, 8, 17},
-		[]Coord{17, 9, 18, 16},
-		[]Coord{18, 10, 19, 17},
-		[]Coord{19, 11, 20, 18},
-		[]Coord{20, 12, 21, 19},
-		[]Coord{21, 13, 22, 20},
-		[]Coord{22, 14, 23, 21},
-		[]Coord{23, 15, 22},
-		[]Coord{24, 16, 25},
-		[]Coord{25, 17, 26, 24},
-		[]Coord{26, 18, 27, 25},
-		[]Coord{27, 19, 28, 26},
-		[]Coord{28, 20, 29, 27},
-		[]Coord{29, 21, 30, 28},
-		[]Coord{30, 22, 31, 29},
-		[]Coord{31, 23, 30},
-		[]Coord{32, 24, 33},
-		[]Coord{33, 25, 34, 32},
-		[]Coord{34, 26, 35, 33},
-		[]Coord{35, 27, 36, 34},
-		[]Coord{36, 28, 37, 35},
-		[]Coord{37, 29, 38, 36},
-		[]Coord{38, 30, 39, 37},
-		[]Coord{39, 31, 38},
-		[]Coord{40, 32, 41},
-		[]Coord{41, 33, 42, 40},
-		[]Coord{42, 34, 43, 41},
-		[]Coord{43, 35, 44, 42},
-		[]Coord{44, 36, 45, 43},
-		[]Coord{45, 37, 46, 44},
-		[]Coord{46, 38, 47, 45},
-		[]Coord{47, 39, 46},
-		[]Coord{48, 40, 49},
-		[]Coord{49, 41, 50, 48},
-		[]Coord{50, 42, 51, 49},
-		[]Coord{51, 43, 52, 50},
-		[]Coord{52, 44, 53, 51},
-		[]Coord{53, 45, 54, 52},
-		[]Coord{54, 46, 55, 53},
-		[]Coord{55, 47, 54},
-	},
-	[64][]Coord{
-		[]Coord{17, 10},
-		[]Coord{18, 11, 16},
-		[]Coord{19, 12, 8, 17},
-		[]Coord{20, 13, 9, 18},
-		[]Coord{21, 14, 10, 19},
-		[]Coord{22, 15, 11, 20},
-		[]Coord{23, 12, 21},
-		[]Coord{13, 22},
-		[]Coord{25, 18, 2},
-		[]Coord{26, 19, 3, 24},
-		[]Coord{27, 20, 4, 0, 16, 25},
-		[]Coord{28, 21, 5, 1, 17, 26},
-		[]Coord{29, 22, 6, 2, 18, 27},
-		[]Coord{30, 23, 7, 3, 19, 28},
-		[]Coord{31, 4, 20, 29},
-		[]Coord{5, 21, 30},
-		[]Coord{33, 26, 10, 1},
-		[]Coord{34, 27, 11, 2, 0, 32},
-		[]Coord{35, 28, 12, 3, 1, 8, 24, 33},
-		[]Coord{36, 29, 13, 4, 2, 9, 25, 34},
-		[]Coord{37, 30, 14, 5, 3, 10, 26, 35},
-		[]Coord{38, 31, 15, 6, 4, 11, 27, 36},
-		[]Coord{39, 7, 5, 12, 28, 37},
-		[]Coord{6, 13, 29, 38},
-		[]Coord{41, 34, 18, 9},
-		[]Coord{42, 35, 19, 10, 8, 40},
-		[]Coord{43, 36, 20, 11, 9, 16, 32, 41},
-		[]Coord{44, 37, 21, 12, 10, 17, 33, 42},
-		[]Coord{45, 38, 22, 13, 11, 18, 34, 43},
-		[]Coord{46, 39, 23, 14, 12, 19, 35, 44},
-		[]Coord{47, 15, 13, 20, 36, 45},
-		[]Coord{14, 21, 37, 46},
-		[]Coord{49, 42, 26, 17},
-		[]Coord{50, 43, 27, 18, 16, 48},
-		[]Coord{51, 44, 28, 19, 17, 24, 40, 49},
-		[]Coord{52, 45, 29, 20, 18, 25, 41, 50},
-		[]Coord{53, 46, 30, 21, 19, 26, 42, 51},
-		[]Coord{54, 47, 31, 22, 20, 27, 43, 52},
-		[]Coord{55, 23, 21, 28, 44, 53},
-		[]Coord{22, 29, 45, 54},
-		[]Coord{57, 50, 34, 25},
-		[]Coord{58, 51, 35, 26, 24, 56},
-		[]Coord{59, 52, 36, 27, 25, 32, 48, 57},
-		[]Coord{60, 53, 37, 28, 26, 33, 49, 58},
-		[]Coord{61, 54, 38, 29, 27, 34, 50, 59},
-		[]Coord{62, 55, 39, 30, 28, 35, 51, 60},
-		[]Coord{63, 31, 29, 36, 52, 61},
-		[]Coord{30, 37, 53, 62},
-		[]Coord{58, 42, 33},
-		[]Coord{59, 43, 34, 32},
-		[]Coord{60, 44, 35, 33, 40, 56},
-		[]Coord{61, 45, 36, 34, 41, 57},
-		[]Coord{62, 46, 37, 35, 42, 58},
-		[]Coord{63, 47, 38, 36, 43, 59},
-		[]Coord{39, 37, 44, 60},
-		[]Coord{38, 45, 61},
-		[]Coord{50, 41},
-		[]Coord{51, 42, 40},
-		[]Coord{52, 43, 41, 48},
-		[]Coord{53, 44, 42, 49},
-		[]Coord{54, 45, 43, 50},
-		[]Coord{55, 46, 44, 51},
-		[]Coord{47, 45, 52},
-		[]Coord{46, 53},
-	},
-	[64][]Coord{},
-	[64][]Coord{},
-	[64][]Coord{},
-	[64][]Coord{
-		[]Coord{8, 9, 1},
-		[]Coord{9, 10, 2, 0, 8},
-		[]Coord{10, 11, 3, 1, 9},
-		[]Coord{11, 12, 4, 2, 10},
-		[]Coord{12, 13, 5, 6, 3, 2, 11},
-		[]Coord{13, 14, 6, 4, 12},
-		[]Coord{14, 15, 7, 5, 13},
-		[]Coord{15, 6, 14},
-		[]Coord{16, 17, 9, 1, 0},
-		[]Coord{17, 18, 10, 2, 1, 0, 8, 16},
-		[]Coord{18, 19, 11, 3, 2, 1, 9, 17},
-		[]Coord{19, 20, 12, 4, 3, 2, 10, 18},
-		[]Coord{20, 21, 13, 14, 5, 4, 3, 11, 10, 19},
-		[]Coord{21, 22, 14, 6, 5, 4, 12, 20},
-		[]Coord{22, 23, 15, 7, 6, 5, 13, 21},
-		[]Coord{23, 7, 6, 14, 22},
-		[]Coord{24, 25, 17, 9, 8},
-		[]Coord{25, 26, 18, 10, 9, 8, 16, 24},
-		[]Coord{26, 27, 19, 11, 10, 9, 17, 25},
-		[]Coord{27, 28, 20, 12, 11, 10, 18, 26},
-		[]Coord{28, 29, 21, 22, 13, 12, 11, 19, 18, 27},
-		[]Coord{29, 30, 22, 14, 13, 12, 20, 28},
-		[]Coord{30, 31, 23, 15, 14, 13, 21, 29},
-		[]Coord{31, 15, 14, 22, 30},
-		[]Coord{32, 33, 25, 17, 16},
-		[]Coord{33, 34, 26, 18, 17, 16, 24, 32},
-		[]Coord{34, 35, 27, 19, 18, 17, 25, 33},
-		[]Coord{35, 36, 28, 20, 19, 18, 26, 34},
-		[]Coord{36, 37, 29, 30, 21, 20, 19, 27, 26, 35},
-		[]Coord{37, 38, 30, 22, 21, 20, 28, 36},
-		[]Coord{38, 39, 31, 23, 22, 21, 29, 37},
-		[]Coord{39, 23, 22, 30, 38},
-		[]Coord{40, 41, 33, 25, 24},
-		[]Coord{41, 42, 34, 26, 25, 24, 32, 40},
-		[]Coord{42, 43, 35, 27, 26, 25, 33, 41},
-		[]Coord{43, 44, 36, 28, 27, 26, 34, 42},
-		[]Coord{44, 45, 37, 38, 29, 28, 27, 35, 34, 43},
-		[]Coord{45, 46, 38, 30, 29, 28, 36, 44},
-		[]Coord{46, 47, 39, 31, 30, 29, 37, 45},
-		[]Coord{47, 31, 30, 38, 46},
-		[]Coord{48, 49, 41, 33, 32},
-		[]Coord{49, 50, 42, 34, 33, 32, 40, 48},
-		[]Coord{50, 51, 43, 35, 34, 33, 41, 49},
-		[]Coord{51, 52, 44, 36, 35, 34, 42, 50},
-		[]Coord{52, 53, 45, 46, 37, 36, 35, 43, 42, 51},
-		[]Coord{53, 54, 46, 38, 37, 36, 44, 52},
-		[]Coord{54, 55, 47, 39, 38, 37, 45, 53},
-		[]Coord{55, 39, 38, 46, 54},
-		[]Coord{56, 57, 49, 41, 40},
-		[]Coord{57, 58, 50, 42, 41, 40, 48, 56},
-		[]Coord{58, 59, 51, 43, 42, 41, 49, 57},
-		[]Coord{59, 60, 52, 44, 43, 42, 50, 58},
-		[]Coord{60, 61, 53, 54, 45, 44, 43, 51, 50, 59},
-		[]Coord{61, 62, 54, 46, 45, 44, 52, 60},
-		[]Coord{62, 63, 55, 47, 46, 45, 53, 61},
-		[]Coord{63, 47, 46, 54, 62},
-		[]Coord{57, 49, 48},
-		[]Coord{58, 50, 49, 48, 56},
-		[]Coord{59, 51, 50, 49, 57},
-		[]Coord{60, 52, 51, 50, 58},
-		[]Coord{61, 62, 53, 52, 51, 59, 58},
-		[]Coord{62, 54, 53, 52, 60},
-		[]Coord{63, 55, 54, 53, 61},
-		[]Coord{55, 54, 62},
-	},
-	[64][]Coord{},
+var movesForPiece = [][64]Bit{
+	[64]Bit{},
+	[64]Bit{0x0000000000010300, 0x0000000000020700, 0x0000000000040e00, 0x0000000000081c00, 0x0000000000103800, 0x0000000000207000, 0x000000000040e000, 0x000000000080c000, 0x0000000001030000, 0x0000000002070000, 0x00000000040e0000, 0x00000000081c0000, 0x0000000010380000, 0x0000000020700000, 0x0000000040e00000, 0x0000000080c00000, 0x0000000103000000, 0x0000000207000000, 0x000000040e000000, 0x000000081c000000, 0x0000001038000000, 0x0000002070000000, 0x00000040e0000000, 0x00000080c0000000, 0x0000010300000000, 0x0000020700000000, 0x0000040e00000000, 0x0000081c00000000, 0x0000103800000000, 0x0000207000000000, 0x000040e000000000, 0x000080c000000000, 0x0001030000000000, 0x0002070000000000, 0x00040e0000000000, 0x00081c0000000000, 0x0010380000000000, 0x0020700000000000, 0x0040e00000000000, 0x0080c00000000000, 0x0103000000000000, 0x0207000000000000, 0x040e000000000000, 0x081c000000000000, 0x1038000000000000, 0x2070000000000000, 0x40e0000000000000, 0x80c0000000000000, 0x0300000000000000, 0x0700000000000000, 0x0e00000000000000, 0x1c00000000000000, 0x3800000000000000, 0x7000000000000000, 0xe000000000000000, 0xc000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000},
+	[64]Bit{0x0000000000020400, 0x0000000000050800, 0x00000000000a1100, 0x0000000000142200, 0x0000000000284400, 0x0000000000508800, 0x0000000000a01000, 0x0000000000402000, 0x0000000002040004, 0x0000000005080008, 0x000000000a110011, 0x0000000014220022, 0x0000000028440044, 0x0000000050880088, 0x00000000a0100010, 0x0000000040200020, 0x0000000204000402, 0x0000000508000805, 0x0000000a1100110a, 0x0000001422002214, 0x0000002844004428, 0x0000005088008850, 0x000000a0100010a0, 0x0000004020002040, 0x0000020400040200, 0x0000050800080500, 0x00000a1100110a00, 0x0000142200221400, 0x0000284400442800, 0x0000508800885000, 0x0000a0100010a000, 0x0000402000204000, 0x0002040004020000, 0x0005080008050000, 0x000a1100110a0000, 0x0014220022140000, 0x0028440044280000, 0x0050880088500000, 0x00a0100010a00000, 0x0040200020400000, 0x0204000402000000, 0x0508000805000000, 0x0a1100110a000000, 0x1422002214000000, 0x2844004428000000, 0x5088008850000000, 0xa0100010a0000000, 0x4020002040000000, 0x0400040200000000, 0x0800080500000000, 0x1100110a00000000, 0x2200221400000000, 0x4400442800000000, 0x8800885000000000, 0x100010a000000000, 0x2000204000000000, 0x0004020000000000, 0x0008050000000000, 0x00110a0000000000, 0x0022140000000000, 0x0044280000000000, 0x0088500000000000, 0x0010a00000000000, 0x0020400000000000},
+	[64]Bit{},
+	[64]Bit{},
+	[64]Bit{},
+	[64]Bit{0x0000000000000302, 0x0000000000000705, 0x0000000000000e0a, 0x0000000000001c14, 0x000000000000386c, 0x0000000000007050, 0x000000000000e0a0, 0x000000000000c040, 0x0000000000030203, 0x0000000000070507, 0x00000000000e0a0e, 0x00000000001c141c, 0x0000000000386c38, 0x0000000000705070, 0x0000000000e0a0e0, 0x0000000000c040c0, 0x0000000003020300, 0x0000000007050700, 0x000000000e0a0e00, 0x000000001c141c00, 0x00000000386c3800, 0x0000000070507000, 0x00000000e0a0e000, 0x00000000c040c000, 0x0000000302030000, 0x0000000705070000, 0x0000000e0a0e0000, 0x0000001c141c0000, 0x000000386c380000, 0x0000007050700000, 0x000000e0a0e00000, 0x000000c040c00000, 0x0000030203000000, 0x0000070507000000, 0x00000e0a0e000000, 0x00001c141c000000, 0x0000386c38000000, 0x0000705070000000, 0x0000e0a0e0000000, 0x0000c040c0000000, 0x0003020300000000, 0x0007050700000000, 0x000e0a0e00000000, 0x001c141c00000000, 0x00386c3800000000, 0x0070507000000000, 0x00e0a0e000000000, 0x00c040c000000000, 0x0302030000000000, 0x0705070000000000, 0x0e0a0e0000000000, 0x1c141c0000000000, 0x386c380000000000, 0x7050700000000000, 0xe0a0e00000000000, 0xc040c00000000000, 0x0203000000000000, 0x0507000000000000, 0x0a0e000000000000, 0x141c000000000000, 0x6c38000000000000, 0x5070000000000000, 0xa0e0000000000000, 0x40c0000000000000},
+	[64]Bit{},
+	[64]Bit{},
+	[64]Bit{0x0000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000003, 0x0000000000000007, 0x000000000000000e, 0x000000000000001c, 0x0000000000000038, 0x0000000000000070, 0x00000000000000e0, 0x00000000000000c0, 0x0000000000000301, 0x0000000000000702, 0x0000000000000e04, 0x0000000000001c08, 0x0000000000003810, 0x0000000000007020, 0x000000000000e040, 0x000000000000c080, 0x0000000000030100, 0x0000000000070200, 0x00000000000e0400, 0x00000000001c0800, 0x0000000000381000, 0x0000000000702000, 0x0000000000e04000, 0x0000000000c08000, 0x0000000003010000, 0x0000000007020000, 0x000000000e040000, 0x000000001c080000, 0x0000000038100000, 0x0000000070200000, 0x00000000e0400000, 0x00000000c0800000, 0x0000000301000000, 0x0000000702000000, 0x0000000e04000000, 0x0000001c08000000, 0x0000003810000000, 0x0000007020000000, 0x000000e040000000, 0x000000c080000000, 0x0000030100000000, 0x0000070200000000, 0x00000e0400000000, 0x00001c0800000000, 0x0000381000000000, 0x0000702000000000, 0x0000e04000000000, 0x0000c08000000000, 0x0003010000000000, 0x0007020000000000, 0x000e040000000000, 0x001c080000000000, 0x0038100000000000, 0x0070200000000000, 0x00e0400000000000, 0x00c0800000000000},
+	[64]Bit{0x0000000000020400, 0x0000000000050800, 0x00000000000a1100, 0x0000000000142200, 0x0000000000284400, 0x0000000000508800, 0x0000000000a01000, 0x0000000000402000, 0x0000000002040004, 0x0000000005080008, 0x000000000a110011, 0x0000000014220022, 0x0000000028440044, 0x0000000050880088, 0x00000000a0100010, 0x0000000040200020, 0x0000000204000402, 0x0000000508000805, 0x0000000a1100110a, 0x0000001422002214, 0x0000002844004428, 0x0000005088008850, 0x000000a0100010a0, 0x0000004020002040, 0x0000020400040200, 0x0000050800080500, 0x00000a1100110a00, 0x0000142200221400, 0x0000284400442800, 0x0000508800885000, 0x0000a0100010a000, 0x0000402000204000, 0x0002040004020000, 0x0005080008050000, 0x000a1100110a0000, 0x0014220022140000, 0x0028440044280000, 0x0050880088500000, 0x00a0100010a00000, 0x0040200020400000, 0x0204000402000000, 0x0508000805000000, 0x0a1100110a000000, 0x1422002214000000, 0x2844004428000000, 0x5088008850000000, 0xa0100010a0000000, 0x4020002040000000, 0x0400040200000000, 0x0800080500000000, 0x1100110a00000000, 0x2200221400000000, 0x4400442800000000, 0x8800885000000000, 0x100010a000000000, 0x2000204000000000, 0x0004020000000000, 0x0008050000000000, 0x00110a0000000000, 0x0022140000000000, 0x0044280000000000, 0x0088500000000000, 0x0010a00000000000, 0x0020400000000000},
+	[64]Bit{},
+	[64]Bit{},
+	[64]Bit{},
+	[64]Bit{0x0000000000000302, 0x0000000000000705, 0x0000000000000e0a, 0x0000000000001c14, 0x000000000000386c, 0x0000000000007050, 0x000000000000e0a0, 0x000000000000c040, 0x0000000000030203, 0x0000000000070507, 0x00000000000e0a0e, 0x00000000001c141c, 0x0000000000386c38, 0x0000000000705070, 0x0000000000e0a0e0, 0x0000000000c040c0, 0x0000000003020300, 0x0000000007050700, 0x000000000e0a0e00, 0x000000001c141c00, 0x00000000386c3800, 0x0000000070507000, 0x00000000e0a0e000, 0x00000000c040c000, 0x0000000302030000, 0x0000000705070000, 0x0000000e0a0e0000, 0x0000001c141c0000, 0x000000386c380000, 0x0000007050700000, 0x000000e0a0e00000, 0x000000c040c00000, 0x0000030203000000, 0x0000070507000000, 0x00000e0a0e000000, 0x00001c141c000000, 0x0000386c38000000, 0x0000705070000000, 0x0000e0a0e0000000, 0x0000c040c0000000, 0x0003020300000000, 0x0007050700000000, 0x000e0a0e00000000, 0x001c141c00000000, 0x00386c3800000000, 0x0070507000000000, 0x00e0a0e000000000, 0x00c040c000000000, 0x0302030000000000, 0x0705070000000000, 0x0e0a0e0000000000, 0x1c141c0000000000, 0x386c380000000000, 0x7050700000000000, 0xe0a0e00000000000, 0xc040c00000000000, 0x0203000000000000, 0x0507000000000000, 0x0a0e000000000000, 0x141c000000000000, 0x6c38000000000000, 0x5070000000000000, 0xa0e0000000000000, 0x40c0000000000000},
+	[64]Bit{},
 }
 
 var wPawnAttacks = [64]Bit{0x0000000000000200, 0x0000000000000500, 0x0000000000000a00, 0x0000000000001400, 0x0000000000002800, 0x0000000000005000, 0x000000000000a000, 0x0000000000004000, 0x0000000000020000, 0x0000000000050000, 0x00000000000a0000, 0x0000000000140000, 0x0000000000280000, 0x0000000000500000, 0x0000000000a00000, 0x0000000000400000, 0x0000000002000000, 0x0000000005000000, 0x000000000a000000, 0x0000000014000000, 0x0000000028000000, 0x0000000050000000, 0x00000000a0000000, 0x0000000040000000, 0x0000000200000000, 0x0000000500000000, 0x0000000a00000000, 0x0000001400000000, 0x0000002800000000, 0x0000005000000000, 0x000000a000000000, 0x0000004000000000, 0x0000020000000000, 0x0000050000000000, 0x00000a0000000000, 0x0000140000000000, 0x0000280000000000, 0x0000500000000000, 0x0000a00000000000, 0x0000400000000000, 0x0002000000000000, 0x0005000000000000, 0x000a000000000000, 0x0014000000000000, 0x0028000000000000, 0x0050000000000000, 0x00a0000000000000, 0x0040000000000000, 0x0200000000000000, 0x0500000000000000, 0x0a00000000000000, 0x1400000000000000, 0x2800000000000000, 0x5000000000000000, 0xa000000000000000, 0x4000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000}
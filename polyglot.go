@@ -0,0 +1,51 @@
+package chess
+
+// The Polyglot hash of a position, as used to key opening books.
+//
+// http://hgm.nubati.net/book_format.html
+
+const (
+	polyglotCastle = 768
+	polyglotEP     = 772
+	polyglotTurn   = 780
+)
+
+// polyglotKind returns the Polyglot piece kind [0..11] for a piece. Polyglot
+// orders the pieces black pawn, white pawn, black knight, white knight, etc.
+func polyglotKind(p Piece) int {
+	kind := 2 * int(p.Colorless()-Pawn)
+	if p.IsWhite() {
+		kind += 1
+	}
+	return kind
+}
+
+// epCapturePossible returns true if the side to move has a pawn that could
+// capture on the en passant target. Polyglot only hashes the en passant file
+// when that's the case.
+func (b *Board) epCapturePossible() bool {
+	ep := b.state.epTarget
+	if ep == InvalidCoord {
+		return false
+	}
+	y, pawn := 4, Piece(White|Pawn)
+	if b.state.turn == Black {
+		y, pawn = 3, Black|Pawn
+	}
+	for _, x := range []int{ep.X() - 1, ep.X() + 1} {
+		if c := CoordFromXY(x, y); c.IsValid() && b.at(c) == pawn {
+			return true
+		}
+	}
+	return false
+}
+
+// PolyglotKey returns the Polyglot hash of the board. Polyglot books, and our
+// own, are keyed by it. It's computed from scratch unless the Board was
+// created WithPolyglotHashing.
+func (b *Board) PolyglotKey() Hash {
+	if b.keys == polyglotKeys {
+		return b.state.hash
+	}
+	return polyglotKeys.hash(b)
+}
@@ -0,0 +1,46 @@
+package chess
+
+import "testing"
+
+func TestPolyglotKey(t *testing.T) {
+	// Test vectors from the Polyglot book format specification.
+	tests := []struct {
+		moves []string
+		key   Hash
+	}{
+		{[]string{}, 0x463b96181691fc9c},
+		{[]string{"e2e4"}, 0x823c9b50fd114196},
+		{[]string{"e2e4", "d7d5"}, 0x0756b94461c50fb0},
+		{[]string{"e2e4", "d7d5", "e4e5"}, 0x662fafb965db29d4},
+		{[]string{"e2e4", "d7d5", "e4e5", "f7f5"}, 0x22a48b5a8e47ff78},
+		{[]string{"e2e4", "d7d5", "e4e5", "f7f5", "e1e2"}, 0x652a607ca3f242c1},
+		{[]string{"e2e4", "d7d5", "e4e5", "f7f5", "e1e2", "e8f7"}, 0x00fdd303c946bdd9},
+		{[]string{"a2a4", "b7b5", "h2h4", "b5b4", "c2c4"}, 0x3c8123ea7b067637},
+		{[]string{"a2a4", "b7b5", "h2h4", "b5b4", "c2c4", "b4c3", "a1a3"}, 0x5c3f9b829b279560},
+	}
+
+	for i, test := range tests {
+		b := New()
+		if err := b.ApplyMoves(test.moves); err != nil {
+			t.Fatalf("[%d] error applying moves: %v", i, err)
+		}
+		if key := b.PolyglotKey(); key != test.key {
+			t.Errorf("[%d] PolyglotKey(%v) = %016x, expected %016x", i, test.moves, key, test.key)
+		}
+
+		// Boards hashing with the Polyglot keys should agree incrementally.
+		b = New(WithPolyglotHashing())
+		if err := b.ApplyMoves(test.moves); err != nil {
+			t.Fatalf("[%d] error applying moves: %v", i, err)
+		}
+		if key := b.ZHash(); key != test.key {
+			t.Errorf("[%d] ZHash(%v) = %016x, expected %016x", i, test.moves, key, test.key)
+		}
+		for range test.moves {
+			b.UnmakeMove()
+		}
+		if key := b.ZHash(); key != tests[0].key {
+			t.Errorf("[%d] ZHash() after unmaking = %016x, expected %016x", i, key, tests[0].key)
+		}
+	}
+}
@@ -0,0 +1,602 @@
+// Package search implements the engine's search: an alpha-beta search of a
+// position, backed by a transposition table and an opening book.
+package search
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"slices"
+	"sync"
+	"time"
+
+	"chess"
+	"chess/book"
+)
+
+type Depth uint8
+
+const (
+	maxScore  = 29999
+	minScore  = -maxScore
+	stalemate = 0
+	checkmate = 10000
+
+	// clockChecks is how many moves the search tries between checks of the
+	// clock against its deadline.
+	clockChecks = 128
+)
+
+func IsMateScore(s chess.Score) bool {
+	return s+50 > checkmate || s-50 < -checkmate
+}
+
+type doneChan chan struct{}
+
+type Eval struct {
+	positions int
+	depth     Depth
+	score     chess.Score
+	seed      int64
+	rand      *rand.Rand
+
+	// benchmark evaluations
+	totalTime time.Duration
+
+	// Functions for starting/stopping evaluation.
+	m        sync.Mutex
+	duration time.Duration
+	ctx      context.Context
+	cancel   context.CancelFunc
+	done     doneChan // Closed when the current evaluation finishes.
+
+	// Pondering. While pondering, the result is held until PonderHit or Stop.
+	pm        sync.Mutex
+	pondering bool
+	held      *searchResult
+	timer     *time.Timer // Stops a search after a PonderHit.
+
+	tt       *TranspositionTable
+	pawns    *PawnHashTable
+	material *MaterialTable
+
+	output io.Writer
+
+	// Options
+	useBook      bool
+	book         book.Source
+	bookSelector book.Selector
+	debug        bool
+}
+
+// searchResult is the move a search found, and the reply it expects.
+type searchResult struct {
+	move, ponder chess.Move
+}
+
+// evalConfig holds the settings an Eval is created with.
+type evalConfig struct {
+	seed int64
+}
+
+// EvalOption configures an Eval when it's created.
+type EvalOption func(*evalConfig)
+
+// WithSeed seeds the Eval's random number generator. See SetSeed.
+func WithSeed(seed int64) EvalOption {
+	return func(c *evalConfig) {
+		c.seed = seed
+	}
+}
+
+// Creates a new Eval.
+func NewEval(depth Depth, opts ...EvalOption) Eval {
+	var cfg evalConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return Eval{
+		depth:    depth,
+		useBook:  true,
+		seed:     cfg.seed,
+		rand:     newRand(cfg.seed),
+		tt:       NewTranspositionTable(20),
+		pawns:    NewPawnHashTable(1),
+		material: NewMaterialTable(1),
+	}
+}
+
+// newRand returns a random number generator with the given seed, or seeded
+// from the clock if the seed is 0.
+func newRand(seed int64) *rand.Rand {
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return rand.New(rand.NewSource(seed))
+}
+
+// SetSeed reseeds the random number generator used for choices like book
+// moves. Evals with the same seed searching the same positions make the same
+// choices, which makes games reproducible. A seed of 0 seeds from the clock.
+func (e *Eval) SetSeed(seed int64) {
+	e.seed = seed
+	e.rand = newRand(seed)
+}
+
+// NewGame stops any evaluation, and resets the Eval for a new game: it clears
+// the tables, the stats, and reseeds the random number generator, so a game
+// doesn't depend on the ones before it.
+func (e *Eval) NewGame() {
+	e.Stop()
+	e.tt.Clear()
+	e.pawns.Clear()
+	e.material.Clear()
+	e.positions, e.score, e.totalTime = 0, 0, 0
+	e.rand = newRand(e.seed)
+}
+
+// ClearHash stops any evaluation, and clears the TranspositionTable.
+func (e *Eval) ClearHash() {
+	e.Stop()
+	e.tt.Clear()
+}
+
+// SetDebug sets the debug state.
+func (e *Eval) SetDebug(v bool) {
+	e.debug = v
+}
+
+// SetBook determines if the evaluation engine will use the book.
+func (e *Eval) SetBook(v bool) {
+	e.useBook = v
+}
+
+// SetOpeningBook sets a book to use in place of the built-in book. A nil book
+// restores the built-in book.
+func (e *Eval) SetOpeningBook(ob book.Source) {
+	e.book = ob
+}
+
+// SetBookSelector sets how moves are chosen from the book.
+func (e *Eval) SetBookSelector(bs book.Selector) {
+	e.bookSelector = bs
+}
+
+func (e *Eval) SetOutput(o io.Writer) {
+	e.output = o
+}
+
+// SetTranspositionTableSize stops any evaluation, and sets the size (in MB) of
+// the TranspositionTable.
+func (e *Eval) SetTranspositionTableSize(sizeMB int) {
+	e.Stop()
+	e.tt.Resize(sizeMB)
+}
+
+// SaveTranspositionTable stops any evaluation, and writes the
+// TranspositionTable. See TranspositionTable.Save.
+func (e *Eval) SaveTranspositionTable(w io.Writer) error {
+	e.Stop()
+	return e.tt.Save(w)
+}
+
+// LoadTranspositionTable stops any evaluation, and reads entries into the
+// TranspositionTable. See TranspositionTable.Load.
+func (e *Eval) LoadTranspositionTable(r io.Reader) error {
+	e.Stop()
+	return e.tt.Load(r)
+}
+
+// SetDuration stops the current evaluation, and
+func (e *Eval) SetDuration(d time.Duration) *Eval {
+	if e.IsRunning() {
+		panic("can't SetDuration on a running Eval")
+	}
+	e.duration = d
+	return e
+}
+
+// IsRunning returns true if the eval engine is running.
+func (e *Eval) IsRunning() bool {
+	e.m.Lock()
+	defer e.m.Unlock()
+	return e.done.running()
+}
+
+// running returns true if the evaluation d signals hasn't finished.
+func (d doneChan) running() bool {
+	if d == nil {
+		return false
+	}
+	select {
+	case <-d:
+		return false
+	default:
+		return true
+	}
+}
+
+// report reports the result of a search, or holds it while pondering.
+func (e *Eval) report(move, ponder chess.Move) {
+	e.pm.Lock()
+	defer e.pm.Unlock()
+
+	if e.pondering {
+		e.held = &searchResult{move: move, ponder: ponder}
+		return
+	}
+	e.writeBestMove(move, ponder)
+}
+
+// writeBestMove writes the bestmove line for a search.
+func (e *Eval) writeBestMove(move, ponder chess.Move) {
+	if e.output == nil {
+		return
+	}
+	line := "bestmove 0000"
+	if !move.IsNull() {
+		line = "bestmove " + move.UCIString()
+	}
+	if !ponder.IsNull() {
+		line += " ponder " + ponder.UCIString()
+	}
+	fmt.Fprintln(e.output, line)
+}
+
+// ponderMove returns the reply we expect to the best move: the next move of
+// the principal variation, or failing that, the transposition table's move.
+func (e *Eval) ponderMove(b *chess.Board, best chess.Move, pv []chess.Move) chess.Move {
+	if len(pv) > 1 && pv[0] == best {
+		return pv[1]
+	}
+	b.MakeMove(best)
+	defer b.UnmakeMove()
+	if m, found := e.tt.move(b.ZHash()); found && slices.Contains(b.PossibleMoves(nil), m) {
+		return m
+	}
+	return chess.Move{}
+}
+
+// sortMoves sorts the possible moves, trying to find good ones first.
+//
+// We also return the number of moves that are special, ie checks, promotions,
+// and captures.
+// /
+// Moves will be sorted in the following order:
+//
+//	[0..X] Checks
+//	[X..Y] Promotions
+//	[Y..Z] Captures
+//	[Z..N] Rest
+func (e *Eval) sortMoves(moves []chess.Move, b *chess.Board) int {
+	// Find likely good moves.
+	idx := 0
+	for i, m := range moves {
+		if m.IsCheck() || m.IsPromotion() || m.IsCapture() {
+			moves[idx], moves[i] = moves[i], moves[idx]
+			idx += 1
+		}
+	}
+
+	// Move checks to the begining.
+	pos := 0
+	for i := pos; i < idx; i++ {
+		if moves[i].IsCheck() {
+			moves[pos], moves[i] = moves[i], moves[pos]
+			pos += 1
+		}
+	}
+
+	// Move promotions to the next place.
+	for i := pos; i < idx; i++ {
+		if moves[i].IsPromotion() {
+			moves[pos], moves[i] = moves[i], moves[pos]
+			pos += 1
+		}
+	}
+
+	return idx
+}
+
+// LoadBook builds the built-in book if the evaluation will use it, so the
+// first search doesn't have to wait for it.
+func (e *Eval) LoadBook() {
+	if e.useBook && e.book == nil {
+		book.Builtin()
+	}
+}
+
+// bookMove looks up a move for the board in the opening book.
+func (e *Eval) bookMove(b *chess.Board) (chess.Move, bool) {
+	ob := e.book
+	if ob == nil {
+		ob = book.Builtin()
+	}
+	return e.bookSelector.Pick(ob, b, e.rand)
+}
+
+// calc evaluates the current position, and returns a score.
+func (e *Eval) calc(b *chess.Board) chess.Score {
+	imbalance, scale, found := e.material.Lookup(b.MaterialKey())
+	if !found {
+		imbalance, scale = evalMaterial(b.MaterialKey())
+		e.material.Insert(b.MaterialKey(), imbalance, scale)
+	}
+	pawns, found := e.pawns.Lookup(b.PawnHash())
+	if !found {
+		pawns = evalPawns(b)
+		e.pawns.Insert(b.PawnHash(), pawns)
+	}
+
+	terms := imbalance + pawns
+	if b.Turn() == chess.Black {
+		terms = -terms
+	}
+	return chess.Score(int(b.CurrentPlayerMaterial()+terms) * int(scale) / scaleNormal)
+}
+
+// reportStats reports the hit rates of the tables the search caches in.
+func (e *Eval) reportStats() {
+	if e.output != nil {
+		fmt.Fprintf(e.output, "info string hash %v\n", e.tt.Stats())
+		fmt.Fprintf(e.output, "info string pawns %v\n", e.pawns.Stats())
+		fmt.Fprintf(e.output, "info string material %v\n", e.material.Stats())
+	}
+}
+
+// Duration returns the length of time the evaluation has run.
+func (e *Eval) Duration() time.Duration {
+	return e.totalTime
+}
+
+// TimeString returns the length of time it took to do the evaluation.
+func (e *Eval) TimeString() string {
+	d := e.Duration()
+	if d.Hours() > 1 {
+		return fmt.Sprintf("%gh", d.Hours())
+	}
+	if d.Minutes() > 1 {
+		return fmt.Sprintf("%gm", d.Minutes())
+	}
+	if d.Seconds() > 1 {
+		return fmt.Sprintf("%gs", d.Seconds())
+	}
+	if d.Milliseconds() > 1 {
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	}
+	if d.Microseconds() > 1 {
+		return fmt.Sprintf("%dus", d.Microseconds())
+	}
+	return fmt.Sprintf("%dns", d.Nanoseconds())
+}
+
+// Stop stops an evaluation. A pondering evaluation stops pondering, and
+// reports its result.
+func (e *Eval) Stop() {
+	e.m.Lock()
+	defer e.m.Unlock()
+
+	e.stopPondering()
+	if e.cancel != nil {
+		e.cancel()
+	}
+	if e.done != nil {
+		<-e.done
+	}
+}
+
+// stopPondering ends pondering, reporting a held result.
+func (e *Eval) stopPondering() {
+	e.pm.Lock()
+	defer e.pm.Unlock()
+
+	e.pondering = false
+	if e.timer != nil {
+		e.timer.Stop()
+		e.timer = nil
+	}
+	if e.held != nil {
+		e.writeBestMove(e.held.move, e.held.ponder)
+		e.held = nil
+	}
+}
+
+// Ponder begins an evaluation on the opponent's time, of the position after
+// the move we expect them to play. It searches until PonderHit or Stop, and
+// holds its result until then.
+func (e *Eval) Ponder(b *chess.Board) {
+	e.Stop()
+	e.pm.Lock()
+	e.pondering = true
+	e.pm.Unlock()
+	e.start(b, 0)
+}
+
+// PonderHit tells a pondering Eval the opponent played the expected move. The
+// search carries on as a normal one, with the Eval's duration starting now.
+func (e *Eval) PonderHit() {
+	e.m.Lock()
+	cancel := e.cancel
+	e.m.Unlock()
+
+	e.pm.Lock()
+	defer e.pm.Unlock()
+	if !e.pondering {
+		return
+	}
+	e.pondering = false
+	if e.held != nil {
+		e.writeBestMove(e.held.move, e.held.ponder)
+		e.held = nil
+		return
+	}
+	if e.duration != 0 && cancel != nil {
+		e.timer = time.AfterFunc(e.duration, cancel)
+	}
+}
+
+// setup creates the context for an evaluation lasting d, or until it's
+// stopped if d is 0.
+func (e *Eval) setup(d time.Duration) {
+	if d != 0 {
+		e.ctx, e.cancel = context.WithTimeout(context.Background(), d)
+	} else {
+		e.ctx, e.cancel = context.WithCancel(context.Background())
+	}
+}
+
+// Wait delays until an evaluation is done.
+func (e *Eval) Wait() {
+	e.m.Lock()
+	done := e.done
+	e.m.Unlock()
+	if done != nil {
+		<-done
+	}
+}
+
+// Start begins an evaluation.
+func (e *Eval) Start(b *chess.Board) {
+	// Stop and previously running evaluation.
+	e.Stop()
+	e.start(b, e.duration)
+}
+
+// start begins an evaluation lasting d, or until it's stopped if d is 0.
+func (e *Eval) start(b *chess.Board, d time.Duration) {
+	// Check the book before taking the lock or starting the clock, so the
+	// lookup can't hold up a Stop or eat into the search's time.
+	if e.useBook {
+		if move, found := e.bookMove(b); found {
+			e.report(move, chess.Move{})
+			return
+		}
+	}
+
+	e.m.Lock()
+	defer e.m.Unlock()
+	e.setup(d)
+	ctx, debug := e.ctx, e.debug
+
+	movesToCheck := make([][]chess.Move, e.depth+1)
+
+	// The context's timer can't fire while the search has the only CPU, so
+	// check the deadline against the clock too, every clockChecks moves.
+	deadline, hasDeadline := ctx.Deadline()
+	var moveCount int
+	var timedOut bool
+	shouldCancel := func() bool {
+		select {
+		case <-ctx.Done():
+			return true
+		default:
+		}
+		if hasDeadline && !timedOut {
+			if moveCount += 1; moveCount%clockChecks == 0 {
+				timedOut = time.Now().After(deadline)
+			}
+		}
+		return timedOut
+	}
+
+	line := []chess.Move{}
+
+	// pv[d] is the principal variation from depth d, and best the root's
+	// best move.
+	pv := make([][]chess.Move, e.depth+2)
+	var best chess.Move
+
+	var search func(Depth, Depth, chess.Score, chess.Score) chess.Score
+	search = func(d, targetD Depth, alpha, beta chess.Score) chess.Score {
+		pv[d] = pv[d][:0]
+
+		// If we've already seen this position, we don't need to keep searching.
+		// At the root, we still need a move to report.
+		if d > 0 {
+			if ttVal, _, found := e.tt.Lookup(b.ZHash(), d, targetD-d, alpha, beta); found {
+				return ttVal
+			}
+		}
+		var bestMove chess.Move
+		evalBound := TTUpper
+
+		// Stats.
+		e.positions += 1
+
+		// Get a link to our local slice.
+		moves := movesToCheck[d][:0]
+		moves = b.PossibleMoves(moves)
+		e.sortMoves(moves, b)
+
+		// If no moves, we could be in stalemate or checkmate.
+		if len(moves) == 0 {
+			if b.IsCheck() {
+				return -(checkmate - chess.Score(d))
+			}
+			return stalemate
+		}
+
+		// If we're done, just calculate the score.
+		// TODO(jfaller): Might want to do this after completing all
+		// checks or captures.
+		if d == targetD {
+			return e.calc(b)
+		}
+
+		// Alpha-beta prune the search tree.
+		for _, move := range moves {
+			if shouldCancel() {
+				break
+			}
+
+			b.MakeMove(move)
+			line = append(line, move)
+			evaluation := -search(d+1, targetD, -beta, -alpha)
+			line = slices.Delete(line, len(line)-1, len(line))
+			b.UnmakeMove()
+
+			// Prune early.
+			if evaluation >= beta {
+				e.tt.Insert(b.ZHash(), move, beta, d, targetD-d, TTLower)
+				return beta
+			}
+			if evaluation > alpha {
+				bestMove = move
+				alpha = evaluation
+				evalBound = TTExact
+				pv[d] = append(append(pv[d][:0], move), pv[d+1]...)
+			}
+		}
+		if !bestMove.IsNull() {
+			e.tt.Insert(b.ZHash(), bestMove, alpha, d, targetD-d, evalBound)
+			if d == 0 {
+				best = bestMove
+			}
+		}
+		return alpha
+	}
+
+	done := make(doneChan)
+	e.done = done
+	go func() {
+		defer close(done)
+		startTime := time.Now()
+		e.score = search(0, e.depth, minScore, maxScore)
+		e.totalTime += time.Now().Sub(startTime)
+		if debug {
+			e.reportStats()
+		}
+
+		// If we were stopped before finding a move, play any legal one.
+		var ponder chess.Move
+		if best.IsNull() {
+			if moves := b.PossibleMoves(nil); len(moves) > 0 {
+				best = moves[0]
+			}
+		} else {
+			ponder = e.ponderMove(b, best, pv[0])
+		}
+		e.report(best, ponder)
+	}()
+}
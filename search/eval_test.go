@@ -0,0 +1,335 @@
+package search
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"chess"
+	"chess/notation"
+)
+
+//go:embed testdata/mate.epd
+var mates string
+
+type evalTest struct {
+	depth Depth
+	fen   string
+}
+
+// getTests returns evalTests from the passed in EPD, which must all have dm
+// operations.
+func getTests(dat string) []evalTest {
+	epds, err := notation.ReadEPD(strings.NewReader(dat))
+	if err != nil {
+		panic(err)
+	}
+	var tests []evalTest
+	for _, e := range epds {
+		if e.Mate == 0 {
+			panic(fmt.Sprintf("no dm in %v", e.FEN))
+		}
+		tests = append(tests, evalTest{depth: Depth(2*e.Mate - 1), fen: e.FEN})
+	}
+	return tests
+}
+
+func TestMoveSorting(t *testing.T) {
+	b, err := chess.FromFEN("8/P7/7k/8/8/2p5/1P6/K2R4 w - - 0 1")
+	if err != nil {
+		t.Fatalf("FromFEN() = %v", err)
+	}
+	moves := make(map[string]chess.Move)
+	for _, s := range []string{"b2b3", "b2c3", "a7a8q", "d1h1"} {
+		if moves[s], err = b.ParseMove(s); err != nil {
+			t.Fatalf("ParseMove(%q) = %v", s, err)
+		}
+	}
+	m, c, p, x := moves["b2b3"], moves["b2c3"], moves["a7a8q"], moves["d1h1"]
+
+	tests := []struct {
+		b, a []chess.Move
+	}{
+		{[]chess.Move{m, p, c, x}, []chess.Move{x, p, c, m}},
+	}
+	for _, test := range tests {
+		sorted := make([]chess.Move, len(test.b))
+		copy(sorted, test.b)
+		e := NewEval(10)
+		e.sortMoves(sorted, b)
+		if !reflect.DeepEqual(sorted, test.a) {
+			t.Errorf("sortMove(%v) = %v, expected %v", test.b, sorted, test.a)
+		}
+	}
+}
+
+func TestMateIn(t *testing.T) {
+	queue := make(chan struct{}, 10)
+
+	// Run the tests.
+	for i, test := range getTests(mates) {
+		test := test
+		name := fmt.Sprintf("test %d, mate in %d", i, test.depth)
+
+		queue <- struct{}{}
+		t.Run(name, func(t *testing.T) {
+			<-queue
+			t.Parallel()
+
+			if test.depth >= 7 {
+				t.Skip("skipping: " + t.Name() + " because it's too long")
+			}
+
+			b, err := chess.FromFEN(test.fen)
+			if err != nil {
+				t.Fatalf("[%d] error in fen %v", i, err)
+			}
+
+			e := NewEval(test.depth)
+			e.Start(b)
+			e.Wait()
+			if !IsMateScore(e.score) {
+				t.Errorf("[%d] was not a checkmate %v, %v", i, test.fen, e.score)
+			}
+		})
+	}
+}
+
+func TestIsMateScore(t *testing.T) {
+	tests := []struct {
+		s      chess.Score
+		isMate bool
+	}{
+		{checkmate, true},
+		{-checkmate, true},
+		{0, false},
+	}
+
+	for i, test := range tests {
+		if v := IsMateScore(test.s); v != test.isMate {
+			t.Errorf("[%d] IsMateScore(%v) = %t, expected %v", i, test.s, v, test.isMate)
+		}
+	}
+}
+
+func TestEvalCancel(t *testing.T) {
+	e := NewEval(100)
+	b, _ := chess.FromFEN("1nbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1")
+	e.Start(b)
+	if !e.IsRunning() {
+		t.Fatalf("expected eval running")
+	}
+	e.Stop()
+	if e.IsRunning() {
+		t.Fatalf("expected eval stopped")
+	}
+}
+
+func TestEvalTimeout(t *testing.T) {
+	t.Parallel()
+	dur := 10 * time.Millisecond
+	e := NewEval(100)
+	e.SetDuration(dur)
+	b, _ := chess.FromFEN("1nbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1")
+	e.Start(b)
+	if !e.IsRunning() {
+		t.Fatalf("expected eval running")
+	}
+	time.Sleep(dur * 2)
+	if e.IsRunning() {
+		t.Fatalf("expected eval stopped")
+	}
+}
+
+func TestEvalSeed(t *testing.T) {
+	// playBook plays book moves from the start position, and then searches
+	// the position the book leaves us in.
+	playBook := func(seed int64) ([]string, int) {
+		e := NewEval(3, WithSeed(seed))
+		b := chess.New()
+		var moves []string
+		for {
+			m, ok := e.bookMove(b)
+			if !ok {
+				break
+			}
+			moves = append(moves, m.UCIString())
+			b.MakeMove(m)
+		}
+		e.SetBook(false)
+		e.Start(b)
+		e.Wait()
+		return moves, e.positions
+	}
+
+	moves, positions := playBook(42)
+	if len(moves) == 0 {
+		t.Fatalf("no book moves played")
+	}
+	for i := 0; i < 3; i++ {
+		m, p := playBook(42)
+		if !reflect.DeepEqual(m, moves) || p != positions {
+			t.Errorf("[%d] seed 42 played %v (%d positions), expected %v (%d positions)", i, m, p, moves, positions)
+		}
+	}
+}
+
+func mateBenchmarker(b *testing.B, d Depth, tests []evalTest) {
+	for j := 0; j < b.N; j++ {
+		for i, test := range getTests(mates) {
+			if test.depth != d {
+				continue
+			}
+			b, err := chess.FromFEN(test.fen)
+			if err != nil {
+				panic(fmt.Sprintf("[%d] error in fen %v", i, err))
+			}
+			e := NewEval(test.depth)
+			e.Start(b)
+			e.Wait()
+			if !IsMateScore(e.score) {
+				panic(fmt.Sprintf("[%d] was not a checkmate %v, %d", i, test.fen, e.score))
+			}
+		}
+	}
+}
+
+func BenchmarkMateIn2(b *testing.B) {
+	mateBenchmarker(b, 2*2-1, getTests(mates))
+}
+
+func BenchmarkMateIn3(b *testing.B) {
+	mateBenchmarker(b, 3*2-1, getTests(mates))
+}
+
+func BenchmarkMateIn4(b *testing.B) {
+	mateBenchmarker(b, 4*2-1, getTests(mates))
+}
+
+func TestEvalNewGame(t *testing.T) {
+	firstMoves := func(e *Eval) (moves []string) {
+		b := chess.New()
+		for i := 0; i < 4; i++ {
+			m, ok := e.bookMove(b)
+			if !ok {
+				break
+			}
+			moves = append(moves, m.UCIString())
+			b.MakeMove(m)
+		}
+		return moves
+	}
+
+	e := NewEval(3, WithSeed(7))
+	expected := firstMoves(&e)
+	e.SetBook(false)
+	b, _ := chess.FromFEN("4k3/pp4pp/8/8/8/8/PP3PPP/4K3 w - - 0 1")
+	e.Start(b)
+	e.Wait()
+	if e.tt.Entries() == 0 || e.pawns.Entries() == 0 || e.material.Entries() == 0 || e.positions == 0 {
+		t.Fatalf("search didn't fill the tables")
+	}
+
+	e.NewGame()
+	if n := e.tt.Entries() + e.pawns.Entries() + e.material.Entries(); n != 0 {
+		t.Errorf("NewGame() left %d table entries", n)
+	}
+	if e.positions != 0 || e.totalTime != 0 || e.score != 0 {
+		t.Errorf("NewGame() left positions %d, time %v, score %v", e.positions, e.totalTime, e.score)
+	}
+	if moves := firstMoves(&e); !reflect.DeepEqual(moves, expected) {
+		t.Errorf("book moves after NewGame() = %v, expected %v", moves, expected)
+	}
+
+	e.Start(b)
+	e.Wait()
+	e.ClearHash()
+	if n := e.tt.Entries(); n != 0 {
+		t.Errorf("ClearHash() left %d entries", n)
+	}
+}
+
+func TestEvalPonder(t *testing.T) {
+	// readOutput returns the lines an Eval wrote to f.
+	readOutput := func(f *os.File) []string {
+		dat, err := os.ReadFile(f.Name())
+		if err != nil {
+			t.Fatalf("error reading output: %v", err)
+		}
+		return strings.FieldsFunc(string(dat), func(r rune) bool { return r == '\n' })
+	}
+
+	tests := []struct {
+		depth     Depth
+		duration  time.Duration // 0 lets the search finish.
+		ponder    bool
+		finish    func(e *Eval)
+		hasPonder bool // A search stopped early might not have a ponder move.
+	}{
+		// A normal search reports when done.
+		{3, 0, false, func(e *Eval) { e.Wait() }, true},
+		// A finished ponder search holds its result until ponderhit.
+		{3, 0, true, func(e *Eval) {
+			e.Wait()
+			time.Sleep(10 * time.Millisecond)
+			e.PonderHit()
+		}, true},
+		// Stopping a ponder search reports its result.
+		{100, 0, true, func(e *Eval) {
+			time.Sleep(50 * time.Millisecond)
+			e.Stop()
+		}, false},
+		// After ponderhit, the search is timed.
+		{100, 50 * time.Millisecond, true, func(e *Eval) {
+			time.Sleep(50 * time.Millisecond)
+			e.PonderHit()
+			e.Wait()
+		}, false},
+	}
+
+	for i, test := range tests {
+		f, err := os.CreateTemp(t.TempDir(), "output")
+		if err != nil {
+			t.Fatalf("[%d] error creating output: %v", i, err)
+		}
+		defer f.Close()
+
+		e := NewEval(test.depth)
+		e.SetBook(false)
+		e.SetOutput(f)
+		e.SetDuration(test.duration)
+		b := chess.New()
+		if test.ponder {
+			e.Ponder(b)
+			time.Sleep(10 * time.Millisecond)
+			if out := readOutput(f); len(out) != 0 {
+				t.Errorf("[%d] output while pondering: %v", i, out)
+			}
+		} else {
+			e.Start(b)
+		}
+		test.finish(&e)
+		e.Stop()
+
+		out := readOutput(f)
+		if len(out) != 1 {
+			t.Fatalf("[%d] output = %v, expected one bestmove", i, out)
+		}
+		fields := strings.Fields(out[0])
+		if len(fields) == 4 && fields[2] == "ponder" {
+			fields = []string{fields[0], fields[1], fields[3]}
+		} else if test.hasPonder {
+			t.Fatalf("[%d] output = %q, expected a ponder move", i, out[0])
+		}
+		if fields[0] != "bestmove" || len(fields) > 3 {
+			t.Fatalf("[%d] output = %q, expected bestmove", i, out[0])
+		}
+		if err := b.ApplyMoves(fields[1:]); err != nil {
+			t.Errorf("[%d] %q isn't legal: %v", i, out[0], err)
+		}
+	}
+}
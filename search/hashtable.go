@@ -0,0 +1,86 @@
+package search
+
+import (
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// tableEntry is an entry in a hashTable.
+type tableEntry interface {
+	// empty returns true if the entry hasn't been filled in.
+	empty() bool
+}
+
+// hashTable is the storage and stats shared by the tables the search caches
+// things in. Each table embeds one, and adds its own index, Lookup and Insert.
+type hashTable[E tableEntry] struct {
+	m    sync.RWMutex
+	vals []E
+
+	// stats
+	lookups atomic.Uint64
+	misses  atomic.Uint64
+	inserts atomic.Uint64
+}
+
+// Resize resizes the table.
+func (h *hashTable[E]) Resize(sizeMB int) {
+	if sizeMB < 0 {
+		panic("invalid hash table size")
+	}
+
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	var e E
+	entries := (sizeMB * 1024 * 1024) / int(unsafe.Sizeof(e))
+	h.vals = make([]E, max(entries, 1))
+	h.clearStats()
+}
+
+// Clear removes all entries from the table.
+func (h *hashTable[E]) Clear() {
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	clear(h.vals)
+	h.clearStats()
+}
+
+// Size returns the number of entries in the table.
+func (h *hashTable[E]) Size() int {
+	return len(h.vals)
+}
+
+// Entries counts the number of non-empty entries.
+func (h *hashTable[E]) Entries() (count int) {
+	h.m.RLock()
+	defer h.m.RUnlock()
+
+	for i := range h.vals {
+		if !h.vals[i].empty() {
+			count += 1
+		}
+	}
+	return count
+}
+
+// clearStats clears the stats.
+func (h *hashTable[E]) clearStats() {
+	h.lookups.Store(0)
+	h.misses.Store(0)
+	h.inserts.Store(0)
+}
+
+// Stats returns the stats structure.
+func (h *hashTable[E]) Stats() TTStats {
+	l, m, i := h.lookups.Load(), h.misses.Load(), h.inserts.Load()
+	return TTStats{
+		Entries: uint64(h.Entries()),
+		Lookups: l,
+		Hits:    l - m,
+		Misses:  m,
+		Inserts: i,
+	}
+}
@@ -0,0 +1,141 @@
+package search
+
+import (
+	"math/bits"
+
+	"chess"
+)
+
+const (
+	// bishopPair is the bonus for having both bishops, in centipawns.
+	bishopPair = 30
+
+	// Scores are scaled by scale/scaleNormal, to pull drawish endgames toward
+	// a draw.
+	scaleNormal  = 64
+	scaleDrawish = 8
+)
+
+// evalMaterial scores the material imbalance from white's point of view, and
+// returns how to scale the position's score.
+func evalMaterial(key chess.MaterialKey) (imbalance chess.Score, scale uint8) {
+	var pieces [2]chess.Score
+	var pawns [2]int
+	colors := [2]chess.Piece{chess.White, chess.Black}
+	for i, color := range colors {
+		if key.Count(color|chess.Bishop) >= 2 {
+			if color == chess.White {
+				imbalance += bishopPair
+			} else {
+				imbalance -= bishopPair
+			}
+		}
+		for _, p := range []chess.Piece{chess.Knight, chess.Bishop, chess.Rook, chess.Queen} {
+			pieces[i] += chess.Score(key.Count(color|p)) * (chess.White | p).Score()
+		}
+		pawns[i] = key.Count(color | chess.Pawn)
+	}
+
+	// Scale toward a draw if the side that's ahead can't win. If neither side
+	// is ahead, it has to be true of both, so the scale is the same with the
+	// colors flipped.
+	cantWin := func(side int) bool {
+		return pawns[side] == 0 && knownDraw(key, colors[side], colors[1-side])
+	}
+	var total [2]chess.Score
+	for i := range total {
+		total[i] = pieces[i] + chess.Score(pawns[i])*chess.Piece(chess.White|chess.Pawn).Score()
+	}
+	var drawish bool
+	switch {
+	case total[0] > total[1]:
+		drawish = cantWin(0)
+	case total[1] > total[0]:
+		drawish = cantWin(1)
+	default:
+		drawish = cantWin(0) && cantWin(1)
+	}
+	if drawish {
+		return imbalance, scaleDrawish
+	}
+	return imbalance, scaleNormal
+}
+
+// knownDraw returns true if the strong side's pieces, without pawns, usually
+// can't beat the weak side's pieces.
+func knownDraw(key chess.MaterialKey, strong, weak chess.Piece) bool {
+	knights, bishops := key.Count(strong|chess.Knight), key.Count(strong|chess.Bishop)
+	minors, rooks, queens := knights+bishops, key.Count(strong|chess.Rook), key.Count(strong|chess.Queen)
+	weakMinors := key.Count(weak|chess.Knight) + key.Count(weak|chess.Bishop)
+	weakRooks, weakQueens := key.Count(weak|chess.Rook), key.Count(weak|chess.Queen)
+
+	switch {
+	case queens+rooks == 0 && minors <= 1:
+		// A lone minor can't mate.
+		return true
+	case queens+rooks == 0 && knights == 2 && bishops == 0:
+		// Nor can two knights, without help.
+		return true
+	case queens == 0 && rooks == 1 && minors == 0:
+		// A rook against a minor.
+		return weakQueens == 0 && weakRooks == 0 && weakMinors == 1
+	case queens == 0 && rooks == 1 && minors == 1:
+		// A rook and minor against a rook.
+		return weakQueens == 0 && weakRooks == 1 && weakMinors == 0
+	}
+	return false
+}
+
+// materialEntry is an entry in a MaterialTable.
+type materialEntry struct {
+	key       chess.MaterialKey
+	imbalance chess.Score
+	scale     uint8
+}
+
+// empty returns true if the entry hasn't been filled in.
+func (e materialEntry) empty() bool {
+	return e.key == 0
+}
+
+// MaterialTable caches the evaluation terms that depend only on the material,
+// keyed by the Board's MaterialKey.
+type MaterialTable struct {
+	hashTable[materialEntry]
+}
+
+// NewMaterialTable creates a new MaterialTable of a given size.
+func NewMaterialTable(sizeMB int) *MaterialTable {
+	mt := &MaterialTable{}
+	mt.Resize(sizeMB)
+	return mt
+}
+
+// index returns the index of the given MaterialKey. The key is just packed
+// counts, so we mix it, and take the index from the high bits of the result.
+func (mt *MaterialTable) index(key chess.MaterialKey) int {
+	hi, _ := bits.Mul64(uint64(key)*0x9e3779b97f4a7c15, uint64(len(mt.vals)))
+	return int(hi)
+}
+
+// Lookup tries to find the imbalance and scale for a MaterialKey.
+func (mt *MaterialTable) Lookup(key chess.MaterialKey) (chess.Score, uint8, bool) {
+	mt.m.RLock()
+	defer mt.m.RUnlock()
+
+	mt.lookups.Add(1)
+	if entry := mt.vals[mt.index(key)]; entry.key == key {
+		return entry.imbalance, entry.scale, true
+	}
+	mt.misses.Add(1)
+	return 0, 0, false
+}
+
+// Insert puts the imbalance and scale for a MaterialKey into the table.
+func (mt *MaterialTable) Insert(key chess.MaterialKey, imbalance chess.Score, scale uint8) {
+	mt.m.Lock()
+	defer mt.m.Unlock()
+
+	mt.inserts.Add(1)
+	mt.vals[mt.index(key)] = materialEntry{key: key, imbalance: imbalance, scale: scale}
+}
@@ -0,0 +1,116 @@
+package search
+
+import (
+	"testing"
+
+	"chess"
+)
+
+func TestEvalMaterial(t *testing.T) {
+	tests := []struct {
+		fen       string
+		imbalance chess.Score
+		scale     uint8
+	}{
+		{chess.StartingFEN, 0, scaleNormal},
+		{"4k3/8/8/8/8/8/8/2B1KB2 w - - 0 1", bishopPair, scaleNormal},
+		{"2b1kb2/8/8/8/8/8/8/4K3 w - - 0 1", -bishopPair, scaleNormal},
+		{"4k3/8/8/8/8/8/8/2B1K3 w - - 0 1", 0, scaleDrawish},
+		{"4kb2/8/8/8/8/8/8/R3K3 w - - 0 1", 0, scaleDrawish},
+		{"4k1n1/8/8/8/8/8/8/4K3 w - - 0 1", 0, scaleDrawish},
+		{"4k3/8/8/8/8/8/P7/2B1K3 w - - 0 1", 0, scaleNormal},
+		{"4k3/8/8/8/8/8/8/Q3K3 w - - 0 1", 0, scaleNormal},
+		{"4k1nn/8/8/8/8/8/8/4K3 w - - 0 1", 0, scaleDrawish},
+		{"3rkb2/8/8/8/8/8/8/R3K3 w - - 0 1", 0, scaleDrawish},
+		{"4k3/8/8/8/8/8/8/RN2K3 w - - 0 1", 0, scaleNormal},
+		{"3rk3/8/8/8/8/8/8/Q3K3 w - - 0 1", 0, scaleNormal},
+		{"2b1k1n1/8/8/8/8/8/8/Q3K3 w - - 0 1", 0, scaleNormal},
+		{"3rk1n1/8/8/8/8/8/8/R1B1K3 w - - 0 1", 0, scaleNormal},
+		{"4k3/8/8/8/8/8/8/R1B1K3 w - - 0 1", 0, scaleNormal},
+		{"3rk3/8/8/8/8/8/8/R1BBK3 w - - 0 1", bishopPair, scaleNormal},
+		{"4k3/8/8/8/8/8/P7/4K3 w - - 0 1", 0, scaleNormal},
+		{"4k3/p7/8/8/8/8/8/4K3 w - - 0 1", 0, scaleNormal},
+		{"4k3/pppp4/8/8/8/8/8/2B1K3 w - - 0 1", 0, scaleNormal},
+		{"2b1k3/8/8/8/8/8/PPPP4/4K3 w - - 0 1", 0, scaleNormal},
+		{"4k3/ppp5/8/8/8/8/8/2B1K3 w - - 0 1", 0, scaleNormal},
+		{"2b1k3/8/8/8/8/8/PPP5/4K3 w - - 0 1", 0, scaleNormal},
+		{"2b1k3/8/8/8/8/8/8/2B1K3 w - - 0 1", 0, scaleDrawish},
+	}
+
+	for i, test := range tests {
+		b, err := chess.FromFEN(test.fen)
+		if err != nil {
+			t.Fatalf("[%d] FromFEN() = %v", i, err)
+		}
+		if imbalance, scale := evalMaterial(b.MaterialKey()); imbalance != test.imbalance || scale != test.scale {
+			t.Errorf("[%d] evalMaterial(%v) = %d, %d, expected %d, %d", i, test.fen, imbalance, scale, test.imbalance, test.scale)
+		}
+	}
+}
+
+func TestMaterialTable(t *testing.T) {
+	mt := NewMaterialTable(1)
+	b := chess.New()
+	if _, _, found := mt.Lookup(b.MaterialKey()); found {
+		t.Errorf("Lookup() found an entry in an empty table")
+	}
+	mt.Insert(b.MaterialKey(), 5, scaleNormal)
+	if imbalance, scale, found := mt.Lookup(b.MaterialKey()); !found || imbalance != 5 || scale != scaleNormal {
+		t.Errorf("Lookup() = %d, %d, %t, expected 5, %d, true", imbalance, scale, found, scaleNormal)
+	}
+	b.ApplyMoves([]string{"e2e4", "d7d5", "e4d5"})
+	if _, _, found := mt.Lookup(b.MaterialKey()); found {
+		t.Errorf("Lookup() found an entry after a capture")
+	}
+
+	stats := mt.Stats()
+	if stats.Entries != 1 || stats.Inserts != 1 || stats.Lookups != 3 || stats.Hits != 1 || stats.Misses != 2 {
+		t.Errorf("Stats() = %+v", stats)
+	}
+}
+
+func TestCalcScale(t *testing.T) {
+	// A bishop up without pawns is scaled toward a draw, for either side to move.
+	for _, fen := range []string{"4k3/8/8/8/8/8/8/2B1K3 w - - 0 1", "4k3/8/8/8/8/8/8/2B1K3 b - - 0 1"} {
+		b, err := chess.FromFEN(fen)
+		if err != nil {
+			t.Fatalf("FromFEN() = %v", err)
+		}
+		e := NewEval(1)
+		expected := chess.Score(300 * scaleDrawish / scaleNormal)
+		if b.Turn() == chess.Black {
+			expected = -expected
+		}
+		if s := e.calc(b); s != expected {
+			t.Errorf("calc(%v) = %d, expected %d", fen, s, expected)
+		}
+		e.calc(b)
+		if stats := e.material.Stats(); stats.Hits != 1 {
+			t.Errorf("material stats = %v, expected 1 hit", stats)
+		}
+	}
+}
+
+func TestCalcColorSymmetry(t *testing.T) {
+	// Each position and its mirror, with colors swapped, score the same for
+	// the side to move.
+	tests := [][2]string{
+		{"4k3/8/8/8/8/8/P7/4K3 w - - 0 1", "4k3/p7/8/8/8/8/8/4K3 b - - 0 1"},
+		{"4k3/pppp4/8/8/8/8/8/2B1K3 w - - 0 1", "2b1k3/8/8/8/8/8/PPPP4/4K3 b - - 0 1"},
+		{"4k3/8/8/8/8/8/8/2B1K3 w - - 0 1", "2b1k3/8/8/8/8/8/8/4K3 b - - 0 1"},
+	}
+	for i, test := range tests {
+		var scores [2]chess.Score
+		for j, fen := range test {
+			b, err := chess.FromFEN(fen)
+			if err != nil {
+				t.Fatalf("[%d] FromFEN() = %v", i, err)
+			}
+			e := NewEval(1)
+			scores[j] = e.calc(b)
+		}
+		if scores[0] != scores[1] {
+			t.Errorf("[%d] calc() = %d, %d, expected them to be equal", i, scores[0], scores[1])
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package search
+
+import "chess"
+
+// Pawn structure scores, in centipawns.
+const (
+	doubledPawn  = -10 // For each pawn behind another on its file.
+	isolatedPawn = -15 // For each pawn with no friendly pawns on adjacent files.
+)
+
+// passedPawn is the bonus for a passed pawn, indexed by how far it has
+// advanced from its side's first rank.
+var passedPawn = [8]chess.Score{0, 5, 10, 20, 35, 60, 100, 0}
+
+// fileA is the set of squares on the a file.
+const fileA = chess.Bit(0x0101010101010101)
+
+// evalPawns scores the pawn structure from white's point of view.
+func evalPawns(b *chess.Board) (score chess.Score) {
+	for _, color := range []chess.Piece{chess.White, chess.Black} {
+		pawns := b.Pieces(color | chess.Pawn)
+		enemy := b.Pieces(color.OppositeColor() | chess.Pawn)
+
+		var s chess.Score
+		for x := 0; x < 8; x++ {
+			file := fileA << x
+			n := chess.Score((pawns & file).CountOnes())
+			if n > 1 {
+				s += doubledPawn * (n - 1)
+			}
+			if n > 0 && pawns&adjacentFiles(x) == 0 {
+				s += isolatedPawn * n
+			}
+		}
+		for v := pawns; v != 0; {
+			c := v.NextCoord()
+			if enemy&frontSpan(c, color) == 0 {
+				rank := c.Y()
+				if color == chess.Black {
+					rank = 7 - rank
+				}
+				s += passedPawn[rank]
+			}
+		}
+
+		if color == chess.Black {
+			s = -s
+		}
+		score += s
+	}
+	return score
+}
+
+// adjacentFiles returns the squares on the files either side of file x.
+func adjacentFiles(x int) (v chess.Bit) {
+	if x > 0 {
+		v |= fileA << (x - 1)
+	}
+	if x < 7 {
+		v |= fileA << (x + 1)
+	}
+	return v
+}
+
+// frontSpan returns the squares in front of a pawn of the given color, on its
+// file and the adjacent ones. A pawn is passed if no enemy pawns are on them.
+func frontSpan(c chess.Coord, color chess.Piece) (v chess.Bit) {
+	files := fileA<<c.X() | adjacentFiles(c.X())
+	for y := 0; y < 8; y++ {
+		if color == chess.White && y > c.Y() || color == chess.Black && y < c.Y() {
+			v |= files & (chess.Bit(0xff) << (8 * y))
+		}
+	}
+	return v
+}
+
+// pawnEntry is an entry in a PawnHashTable.
+type pawnEntry struct {
+	hash  chess.Hash
+	score chess.Score
+}
+
+// empty returns true if the entry hasn't been filled in.
+func (e pawnEntry) empty() bool {
+	return e.hash == 0
+}
+
+// PawnHashTable caches pawn structure scores, keyed by the Board's PawnHash.
+type PawnHashTable struct {
+	hashTable[pawnEntry]
+}
+
+// NewPawnHashTable creates a new PawnHashTable of a given size.
+func NewPawnHashTable(sizeMB int) *PawnHashTable {
+	pt := &PawnHashTable{}
+	pt.Resize(sizeMB)
+	return pt
+}
+
+// index returns the index of the given Hash.
+func (pt *PawnHashTable) index(hash chess.Hash) int {
+	return int(hash % chess.Hash(len(pt.vals)))
+}
+
+// Lookup tries to find the pawn structure score for a PawnHash.
+func (pt *PawnHashTable) Lookup(hash chess.Hash) (chess.Score, bool) {
+	pt.m.RLock()
+	defer pt.m.RUnlock()
+
+	pt.lookups.Add(1)
+	if entry := pt.vals[pt.index(hash)]; entry.hash == hash {
+		return entry.score, true
+	}
+	pt.misses.Add(1)
+	return 0, false
+}
+
+// Insert puts a pawn structure score into the table.
+func (pt *PawnHashTable) Insert(hash chess.Hash, score chess.Score) {
+	pt.m.Lock()
+	defer pt.m.Unlock()
+
+	pt.inserts.Add(1)
+	pt.vals[pt.index(hash)] = pawnEntry{hash: hash, score: score}
+}
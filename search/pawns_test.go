@@ -0,0 +1,59 @@
+package search
+
+import (
+	"testing"
+
+	"chess"
+)
+
+func TestEvalPawns(t *testing.T) {
+	tests := []struct {
+		fen   string
+		score chess.Score
+	}{
+		{chess.StartingFEN, 0},
+		{"4k3/8/8/8/8/8/P7/4K3 w - - 0 1", isolatedPawn + passedPawn[1]},
+		{"4k3/8/8/8/8/P7/P7/4K3 w - - 0 1", doubledPawn + 2*isolatedPawn + passedPawn[1] + passedPawn[2]},
+		{"4k3/p7/p7/8/8/8/8/4K3 w - - 0 1", -(doubledPawn + 2*isolatedPawn + passedPawn[1] + passedPawn[2])},
+		{"4k3/1p6/8/P7/8/8/8/4K3 w - - 0 1", 0}, // Isolated, but neither is passed.
+		{"4k3/8/8/8/8/8/8/4K3 w - - 0 1", 0},
+	}
+
+	for i, test := range tests {
+		b, err := chess.FromFEN(test.fen)
+		if err != nil {
+			t.Fatalf("[%d] FromFEN() = %v", i, err)
+		}
+		if s := evalPawns(b); s != test.score {
+			t.Errorf("[%d] evalPawns(%v) = %d, expected %d", i, test.fen, s, test.score)
+		}
+	}
+}
+
+func TestPawnHashTable(t *testing.T) {
+	pt := NewPawnHashTable(1)
+	b := chess.New()
+	if _, found := pt.Lookup(b.PawnHash()); found {
+		t.Errorf("Lookup() found an entry in an empty table")
+	}
+	pt.Insert(b.PawnHash(), 12)
+	if s, found := pt.Lookup(b.PawnHash()); !found || s != 12 {
+		t.Errorf("Lookup() = %d, %t, expected 12, true", s, found)
+	}
+	b.ApplyMoves([]string{"e2e4"})
+	if _, found := pt.Lookup(b.PawnHash()); found {
+		t.Errorf("Lookup() found an entry after a pawn move")
+	}
+
+	stats := pt.Stats()
+	if stats.Entries != 1 || stats.Inserts != 1 || stats.Lookups != 3 || stats.Hits != 1 || stats.Misses != 2 {
+		t.Errorf("Stats() = %+v", stats)
+	}
+	if r := stats.HitRate(); r != 1.0/3 {
+		t.Errorf("HitRate() = %v, expected %v", r, 1.0/3)
+	}
+	pt.Clear()
+	if stats := pt.Stats(); stats != (TTStats{}) {
+		t.Errorf("Stats() after Clear() = %+v", stats)
+	}
+}
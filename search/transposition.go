@@ -0,0 +1,129 @@
+package search
+
+import (
+	"fmt"
+
+	"chess"
+)
+
+type TTType uint8
+
+const (
+	TTExact TTType = iota
+	TTUpper
+	TTLower
+)
+
+// TTStats are the usage stats of a TranspositionTable, or the other tables
+// the search caches evaluations in.
+type TTStats struct {
+	Entries uint64
+	Inserts uint64
+	Lookups uint64
+	Hits    uint64
+	Misses  uint64
+}
+
+// HitRate returns the fraction of lookups that were hits.
+func (s TTStats) HitRate() float64 {
+	if s.Lookups == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Lookups)
+}
+
+// String returns the stats in a form suitable for logging.
+func (s TTStats) String() string {
+	return fmt.Sprintf("entries %d inserts %d lookups %d hits %d (%.1f%%)",
+		s.Entries, s.Inserts, s.Lookups, s.Hits, 100*s.HitRate())
+}
+
+// ttEntry is an entry in a TranspositionTable.
+type ttEntry struct {
+	hash  chess.Hash
+	move  chess.Move
+	score chess.Score
+	depth Depth
+	t     TTType
+}
+
+// empty returns true if the entry hasn't been filled in.
+func (e ttEntry) empty() bool {
+	return e.hash == 0
+}
+
+// TranspositionTable holds transpositions.
+type TranspositionTable struct {
+	hashTable[ttEntry]
+}
+
+// NewTranspositionTable creates a new TranspositionTable of a given size.
+func NewTranspositionTable(sizeMB int) *TranspositionTable {
+	tt := &TranspositionTable{}
+	tt.Resize(sizeMB)
+	return tt
+}
+
+// index returns the index of the given Hash.
+func (tt *TranspositionTable) index(hash chess.Hash) int {
+	return int(hash % chess.Hash(len(tt.vals)))
+}
+
+// Lookup tries to find an entry in the TranspositionTable.
+func (tt *TranspositionTable) Lookup(hash chess.Hash, depth, plyRemain Depth, alpha, beta chess.Score) (chess.Score, chess.Move, bool) {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+
+	tt.lookups.Add(1)
+	entry := tt.vals[tt.index(hash)]
+	if entry.hash == hash && entry.depth >= plyRemain {
+		score := correctScore(entry.score, depth)
+		if entry.t == TTExact {
+			return score, entry.move, true
+		}
+		if entry.t == TTUpper && score <= alpha {
+			return score, entry.move, true
+		}
+		if entry.t == TTLower && score >= beta {
+			return score, entry.move, true
+		}
+	}
+	tt.misses.Add(1)
+	return 0, chess.Move{}, false
+}
+
+// move returns the move stored for a hash, if there is one.
+func (tt *TranspositionTable) move(hash chess.Hash) (chess.Move, bool) {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+
+	entry := tt.vals[tt.index(hash)]
+	return entry.move, entry.hash == hash && !entry.move.IsNull()
+}
+
+// Insert puts an entry into the transposition table.
+func (tt *TranspositionTable) Insert(hash chess.Hash, move chess.Move, score chess.Score, plySearched, plyRemain Depth, evalType TTType) {
+	tt.m.Lock()
+	defer tt.m.Unlock()
+
+	tt.inserts.Add(1)
+	tt.vals[tt.index(hash)] = ttEntry{
+		hash:  hash,
+		move:  move,
+		score: correctScore(score, plySearched),
+		depth: plyRemain,
+		t:     evalType,
+	}
+}
+
+// correctScore
+func correctScore(score chess.Score, numPly Depth) chess.Score {
+	if IsMateScore(score) {
+		sign := chess.Score(1)
+		if score < 0 {
+			sign = -1
+		}
+		return (score*sign - chess.Score(numPly)) * sign
+	}
+	return score
+}
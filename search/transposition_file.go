@@ -0,0 +1,174 @@
+package search
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+
+	"chess"
+)
+
+// A saved TranspositionTable is a ttFileHeader, the non-empty entries each
+// ttFileEntrySize bytes long, and a CRC-32 (IEEE) of everything before it. It's
+// all little endian.
+const (
+	ttFileMagic     = "GCTT"
+	ttFileVersion   = 1
+	ttFileEntrySize = 8 + 5 + 2 + 1 + 1 // hash, move, score, depth, type
+)
+
+// ttFileHeader describes a saved TranspositionTable. The hashes are only good
+// with the keys they were made with, so we save the scheme, and the starting
+// position's hash to catch the keys being regenerated.
+type ttFileHeader struct {
+	Magic     [4]byte
+	Version   uint32
+	Scheme    chess.HashScheme
+	_         [3]byte
+	StartHash chess.Hash
+	EntrySize uint32
+	_         [4]byte
+	Entries   uint64
+}
+
+// newTTFileHeader returns the header for a table with the given entries.
+func newTTFileHeader(entries uint64) ttFileHeader {
+	b := chess.New()
+	h := ttFileHeader{
+		Version:   ttFileVersion,
+		Scheme:    b.HashScheme(),
+		StartHash: b.ZHash(),
+		EntrySize: ttFileEntrySize,
+		Entries:   entries,
+	}
+	copy(h.Magic[:], ttFileMagic)
+	return h
+}
+
+// check returns an error if a header read from a file doesn't match ours.
+func (h ttFileHeader) check() error {
+	exp := newTTFileHeader(h.Entries)
+	switch {
+	case h.Magic != exp.Magic:
+		return errors.New("not a transposition table file")
+	case h.Version != exp.Version:
+		return fmt.Errorf("unsupported transposition table version: %d", h.Version)
+	case h.Scheme != exp.Scheme || h.StartHash != exp.StartHash:
+		return errors.New("transposition table hashed with different keys")
+	case h.EntrySize != exp.EntrySize:
+		return fmt.Errorf("unsupported transposition table entry size: %d", h.EntrySize)
+	}
+	return nil
+}
+
+// marshal encodes the entry into a buffer ttFileEntrySize bytes long.
+func (e *ttEntry) marshal(buf []byte) {
+	binary.LittleEndian.PutUint64(buf[0:], uint64(e.hash))
+	move, _ := e.move.MarshalBinary()
+	copy(buf[8:13], move)
+	binary.LittleEndian.PutUint16(buf[13:], uint16(e.score))
+	buf[15] = byte(e.depth)
+	buf[16] = byte(e.t)
+}
+
+// unmarshal decodes an entry encoded by marshal.
+func (e *ttEntry) unmarshal(buf []byte) error {
+	e.hash = chess.Hash(binary.LittleEndian.Uint64(buf[0:]))
+	if err := e.move.UnmarshalBinary(buf[8:13]); err != nil {
+		return err
+	}
+	e.score = chess.Score(binary.LittleEndian.Uint16(buf[13:]))
+	e.depth = Depth(buf[15])
+	e.t = TTType(buf[16])
+	if e.t > TTLower {
+		return fmt.Errorf("invalid entry type: %d", e.t)
+	}
+	return nil
+}
+
+// Save writes the TranspositionTable's entries, to be read with Load.
+func (tt *TranspositionTable) Save(w io.Writer) error {
+	tt.m.RLock()
+	defer tt.m.RUnlock()
+
+	var entries uint64
+	for i := range tt.vals {
+		if tt.vals[i].hash != 0 {
+			entries += 1
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	crc := crc32.NewIEEE()
+	out := io.MultiWriter(bw, crc)
+	if err := binary.Write(out, binary.LittleEndian, newTTFileHeader(entries)); err != nil {
+		return fmt.Errorf("error writing transposition table: %w", err)
+	}
+	var buf [ttFileEntrySize]byte
+	for i := range tt.vals {
+		if tt.vals[i].hash == 0 {
+			continue
+		}
+		tt.vals[i].marshal(buf[:])
+		if _, err := out.Write(buf[:]); err != nil {
+			return fmt.Errorf("error writing transposition table: %w", err)
+		}
+	}
+	if err := binary.Write(bw, binary.LittleEndian, crc.Sum32()); err != nil {
+		return fmt.Errorf("error writing transposition table: %w", err)
+	}
+	return bw.Flush()
+}
+
+// Load reads entries written by Save into the TranspositionTable, which can be
+// a different size than the saved one. Where entries collide, the deeper one
+// is kept. The table is left unchanged if the file is invalid or corrupted.
+func (tt *TranspositionTable) Load(r io.Reader) error {
+	crc := crc32.NewIEEE()
+	in := io.TeeReader(bufio.NewReader(r), crc)
+
+	var h ttFileHeader
+	if err := binary.Read(in, binary.LittleEndian, &h); err != nil {
+		return fmt.Errorf("error reading transposition table header: %w", err)
+	}
+	if err := h.check(); err != nil {
+		return err
+	}
+
+	var entries []ttEntry
+	var buf [ttFileEntrySize]byte
+	for i := uint64(0); i < h.Entries; i++ {
+		if _, err := io.ReadFull(in, buf[:]); err != nil {
+			return fmt.Errorf("error reading transposition table entry %d: %w", i, err)
+		}
+		var e ttEntry
+		if err := e.unmarshal(buf[:]); err != nil {
+			return fmt.Errorf("error reading transposition table entry %d: %w", i, err)
+		}
+		entries = append(entries, e)
+	}
+	sum := crc.Sum32()
+	var saved uint32
+	if err := binary.Read(in, binary.LittleEndian, &saved); err != nil {
+		return fmt.Errorf("error reading transposition table checksum: %w", err)
+	}
+	if saved != sum {
+		return fmt.Errorf("transposition table checksum mismatch: %08x, expected %08x", saved, sum)
+	}
+
+	tt.m.Lock()
+	defer tt.m.Unlock()
+	if len(tt.vals) == 0 {
+		return nil
+	}
+	for _, e := range entries {
+		v := &tt.vals[tt.index(e.hash)]
+		if v.hash == 0 || e.depth >= v.depth {
+			*v = e
+		}
+	}
+	return nil
+}
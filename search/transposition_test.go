@@ -0,0 +1,99 @@
+package search
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	"chess"
+)
+
+func TestTranspositionSize(t *testing.T) {
+	t.SkipNow()
+	tt := NewTranspositionTable(1)
+	s1 := tt.Size()
+	tt.Resize(10)
+	s2 := tt.Size()
+	t.Logf("elements before: %d, elements after: %d", s1, s2)
+	if s1 == 0 || s2 == 0 || s1 >= s2 || s1*10 > s2 {
+		t.Errorf("table didn't Resize properly s1: %d, s2: %d", s1, s2)
+	}
+}
+
+// saveTestTable returns a table with entries for the positions after each
+// move from the start, and its saved contents.
+func saveTestTable(t *testing.T) (*TranspositionTable, []chess.Hash, []byte) {
+	t.Helper()
+	tt := NewTranspositionTable(1)
+	b := chess.New()
+	var hashes []chess.Hash
+	for i, m := range b.PossibleMoves(nil) {
+		b.MakeMove(m)
+		tt.Insert(b.ZHash(), m, chess.Score(i-10), 0, Depth(i%5), TTExact)
+		hashes = append(hashes, b.ZHash())
+		b.UnmakeMove()
+	}
+	var buf bytes.Buffer
+	if err := tt.Save(&buf); err != nil {
+		t.Fatalf("Save() = %v", err)
+	}
+	return tt, hashes, buf.Bytes()
+}
+
+func TestTranspositionSaveLoad(t *testing.T) {
+	tt, hashes, data := saveTestTable(t)
+	if exp := binary.Size(ttFileHeader{}) + len(hashes)*ttFileEntrySize + 4; len(data) != exp {
+		t.Errorf("Save() wrote %d bytes, expected %d", len(data), exp)
+	}
+
+	// Load into a table of a different size.
+	loaded := NewTranspositionTable(2)
+	if err := loaded.Load(bytes.NewReader(data)); err != nil {
+		t.Fatalf("Load() = %v", err)
+	}
+	if n := loaded.Entries(); n != len(hashes) {
+		t.Errorf("Entries() = %d, expected %d", n, len(hashes))
+	}
+	for i, h := range hashes {
+		s1, m1, ok1 := tt.Lookup(h, 0, 0, minScore, maxScore)
+		s2, m2, ok2 := loaded.Lookup(h, 0, 0, minScore, maxScore)
+		if !ok1 || !ok2 || s1 != s2 || m1 != m2 {
+			t.Errorf("[%d] Lookup() = %v, %v, %t, expected %v, %v, %t", i, s2, m2, ok2, s1, m1, ok1)
+		}
+	}
+}
+
+func TestTranspositionLoadErrors(t *testing.T) {
+	_, _, data := saveTestTable(t)
+	corrupt := func(f func([]byte) []byte) []byte {
+		return f(bytes.Clone(data))
+	}
+	tests := []struct {
+		desc string
+		data []byte
+		err  string
+	}{
+		{"empty", nil, "header"},
+		{"magic", corrupt(func(d []byte) []byte { d[0] = 'X'; return d }), "not a transposition table"},
+		{"version", corrupt(func(d []byte) []byte { d[4] = 2; return d }), "version"},
+		{"scheme", corrupt(func(d []byte) []byte { d[8] = byte(chess.PolyglotHashing); return d }), "different keys"},
+		{"keys", corrupt(func(d []byte) []byte { d[12] ^= 1; return d }), "different keys"},
+		{"entry size", corrupt(func(d []byte) []byte { d[20] = 16; return d }), "entry size"},
+		{"truncated", data[:len(data)-10], "entry"},
+		{"no checksum", data[:len(data)-4], "checksum"},
+		{"entry", corrupt(func(d []byte) []byte { d[40+15] ^= 1; return d }), "checksum mismatch"},
+		{"checksum", corrupt(func(d []byte) []byte { d[len(d)-1] ^= 1; return d }), "checksum mismatch"},
+	}
+
+	for _, test := range tests {
+		tt := NewTranspositionTable(1)
+		err := tt.Load(bytes.NewReader(test.data))
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("[%s] Load() = %v, expected %q error", test.desc, err, test.err)
+		}
+		if n := tt.Entries(); n != 0 {
+			t.Errorf("[%s] Load() left %d entries, expected 0", test.desc, n)
+		}
+	}
+}
@@ -1,33 +0,0 @@
-package main
-
-import (
-	"reflect"
-)
-
-func getSize(v interface{}) int {
-	size := int(reflect.TypeOf(v).Size())
-	switch reflect.TypeOf(v).Kind() {
-	case reflect.Array, reflect.Slice:
-		s := reflect.ValueOf(v)
-		for i := 0; i < s.Len(); i++ {
-			size += getSize(s.Index(i).Interface())
-		}
-	case reflect.Map:
-		s := reflect.ValueOf(v)
-		keys := s.MapKeys()
-		size += int(float64(len(keys)) * 10.79) // approximation from https://golang.org/src/runtime/hashmap.go
-		for i := range keys {
-			size += getSize(keys[i].Interface()) + getSize(s.MapIndex(keys[i]).Interface())
-		}
-	case reflect.String:
-		size += reflect.ValueOf(v).Len()
-	case reflect.Struct:
-		s := reflect.ValueOf(v)
-		for i := 0; i < s.NumField(); i++ {
-			if s.Field(i).CanInterface() {
-				size += getSize(s.Field(i).Interface())
-			}
-		}
-	}
-	return size
-}
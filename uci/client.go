@@ -0,0 +1,284 @@
+package uci
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrClosed is returned when the engine's output closes, usually because it
+// exited, while the Client is waiting on it.
+var ErrClosed = errors.New("engine output closed")
+
+// Client talks to a UCI engine.
+//
+// Commands are written to the engine's input, and its output is read in the
+// background, so waiting for a response can be cancelled or time out with a
+// context. A Client isn't safe for concurrent use.
+type Client struct {
+	// Set by the handshake.
+	Name    string
+	Author  string
+	Options map[string]Option
+
+	w      io.Writer
+	lines  chan string
+	done   chan struct{} // Closed when the engine's output has closed.
+	quit   chan struct{} // Closed when we no longer want the engine's output.
+	closer sync.Once
+}
+
+// NewClient creates a Client for an engine, writing commands to w and
+// reading responses from r.
+func NewClient(r io.Reader, w io.Writer) *Client {
+	c := &Client{
+		Options: make(map[string]Option),
+		w:       w,
+		lines:   make(chan string, 64),
+		done:    make(chan struct{}),
+		quit:    make(chan struct{}),
+	}
+	go func() {
+		defer close(c.done)
+		defer close(c.lines)
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			select {
+			case c.lines <- scanner.Text():
+			case <-c.quit:
+			}
+		}
+	}()
+	return c
+}
+
+// Done returns a channel that's closed when the engine's output has closed.
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+// Send writes a command to the engine.
+func (c *Client) Send(cmd string) error {
+	if _, err := io.WriteString(c.w, cmd+"\n"); err != nil {
+		return fmt.Errorf("error writing %q command: %w", cmd, err)
+	}
+	return nil
+}
+
+// readLine returns the next line of the engine's output.
+func (c *Client) readLine(ctx context.Context) (string, error) {
+	select {
+	case line, ok := <-c.lines:
+		if !ok {
+			return "", ErrClosed
+		}
+		return strings.TrimSpace(line), nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// Handshake sends "uci", recording the engine's id and options until it
+// replies "uciok".
+func (c *Client) Handshake(ctx context.Context) error {
+	if err := c.Send("uci"); err != nil {
+		return err
+	}
+	for {
+		line, err := c.readLine(ctx)
+		if err != nil {
+			return fmt.Errorf("waiting for uciok: %w", err)
+		}
+		switch {
+		case line == "uciok":
+			return nil
+		case strings.HasPrefix(line, "id name "):
+			c.Name = strings.TrimPrefix(line, "id name ")
+		case strings.HasPrefix(line, "id author "):
+			c.Author = strings.TrimPrefix(line, "id author ")
+		case strings.HasPrefix(line, "option "):
+			// Tolerate options we can't parse, so we can still use the engine.
+			if opt, err := ParseOption(line); err == nil {
+				c.Options[opt.Name] = opt
+			}
+		}
+	}
+}
+
+// IsReady sends "isready", and waits for "readyok".
+func (c *Client) IsReady(ctx context.Context) error {
+	if err := c.Send("isready"); err != nil {
+		return err
+	}
+	for {
+		line, err := c.readLine(ctx)
+		if err != nil {
+			return fmt.Errorf("waiting for readyok: %w", err)
+		}
+		if line == "readyok" {
+			return nil
+		}
+	}
+}
+
+// SetOption sets one of the engine's options.
+func (c *Client) SetOption(name, value string) error {
+	if len(value) == 0 {
+		return c.Send("setoption name " + name)
+	}
+	return c.Send(fmt.Sprintf("setoption name %s value %s", name, value))
+}
+
+// NewGame tells the engine a new game is starting, and waits for it to be
+// ready.
+func (c *Client) NewGame(ctx context.Context) error {
+	if err := c.Send("ucinewgame"); err != nil {
+		return err
+	}
+	return c.IsReady(ctx)
+}
+
+// Position sets the position to search, from a FEN (or the starting position
+// if fen is empty) followed by moves in long algebraic notation.
+func (c *Client) Position(fen string, moves []string) error {
+	cmd := "position startpos"
+	if len(fen) != 0 {
+		cmd = "position fen " + fen
+	}
+	if len(moves) != 0 {
+		cmd += " moves " + strings.Join(moves, " ")
+	}
+	return c.Send(cmd)
+}
+
+// GoParams are the limits of a search.
+type GoParams struct {
+	WTime, BTime time.Duration
+	WInc, BInc   time.Duration
+	MovesToGo    int
+	Depth        int
+	Nodes        int64
+	MoveTime     time.Duration
+	Infinite     bool
+	Ponder       bool
+}
+
+// String returns the "go" command for the parameters.
+func (p GoParams) String() string {
+	var sb strings.Builder
+	sb.WriteString("go")
+	if p.Ponder {
+		sb.WriteString(" ponder")
+	}
+	if p.WTime != 0 || p.BTime != 0 {
+		fmt.Fprintf(&sb, " wtime %d btime %d", p.WTime.Milliseconds(), p.BTime.Milliseconds())
+	}
+	if p.WInc != 0 || p.BInc != 0 {
+		fmt.Fprintf(&sb, " winc %d binc %d", p.WInc.Milliseconds(), p.BInc.Milliseconds())
+	}
+	if p.MovesToGo != 0 {
+		fmt.Fprintf(&sb, " movestogo %d", p.MovesToGo)
+	}
+	if p.Depth != 0 {
+		fmt.Fprintf(&sb, " depth %d", p.Depth)
+	}
+	if p.Nodes != 0 {
+		fmt.Fprintf(&sb, " nodes %d", p.Nodes)
+	}
+	if p.MoveTime != 0 {
+		fmt.Fprintf(&sb, " movetime %d", p.MoveTime.Milliseconds())
+	}
+	if p.Infinite {
+		sb.WriteString(" infinite")
+	}
+	return sb.String()
+}
+
+// Go starts a search, and waits for the engine's best move (see Wait).
+//
+// If ctx is done before the engine replies, the error wraps ctx's error, and
+// the engine is still searching; it can be stopped with Stop, and its move
+// collected with Wait.
+func (c *Client) Go(ctx context.Context, p GoParams, onInfo func(Info)) (BestMove, Info, error) {
+	if err := c.Send(p.String()); err != nil {
+		return BestMove{}, Info{}, err
+	}
+	return c.Wait(ctx, onInfo)
+}
+
+// Wait waits for the best move of a search. Each info line the engine sends
+// is passed to onInfo, if it isn't nil, and lines that don't parse are
+// skipped. The last info line with a score is returned along with the best
+// move.
+func (c *Client) Wait(ctx context.Context, onInfo func(Info)) (BestMove, Info, error) {
+	var last Info
+	for {
+		line, err := c.readLine(ctx)
+		if err != nil {
+			return BestMove{}, last, fmt.Errorf("waiting for bestmove: %w", err)
+		}
+		switch {
+		case strings.HasPrefix(line, "info "):
+			info, err := ParseInfo(line)
+			if err != nil {
+				continue
+			}
+			if info.HasScore {
+				last = info
+			}
+			if onInfo != nil {
+				onInfo(info)
+			}
+		case strings.HasPrefix(line, "bestmove"):
+			bm, err := ParseBestMove(line)
+			return bm, last, err
+		}
+	}
+}
+
+// Perft runs "go perft", an extension of UCI supported by Stockfish and
+// others, returning the number of leaf nodes at depth below each legal move,
+// keyed by the move, and their total.
+func (c *Client) Perft(ctx context.Context, depth int) (map[string]uint64, uint64, error) {
+	if err := c.Send(fmt.Sprintf("go perft %d", depth)); err != nil {
+		return nil, 0, err
+	}
+	counts := make(map[string]uint64)
+	for {
+		line, err := c.readLine(ctx)
+		if err != nil {
+			return nil, 0, fmt.Errorf("waiting for perft: %w", err)
+		}
+		key, value, ok := strings.Cut(line, ": ")
+		if !ok {
+			continue
+		}
+		n, err := strconv.ParseUint(value, 10, 64)
+		switch {
+		case err != nil:
+			continue
+		case key == "Nodes searched":
+			return counts, n, nil
+		case !strings.Contains(key, " "):
+			counts[key] = n
+		}
+	}
+}
+
+// Stop tells the engine to stop searching.
+func (c *Client) Stop() error {
+	return c.Send("stop")
+}
+
+// Quit tells the engine to exit. Any further output from the engine is
+// discarded.
+func (c *Client) Quit() error {
+	c.closer.Do(func() { close(c.quit) })
+	return c.Send("quit")
+}
@@ -0,0 +1,195 @@
+package uci
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// script runs a fake engine that answers each command it reads with the
+// lines in replies, exiting after the last command it knows. It returns the
+// commands it received once it exits.
+func script(t *testing.T, replies map[string][]string, last string) (*Client, <-chan []string) {
+	t.Helper()
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	c := NewClient(outR, inW)
+	received := make(chan []string, 1)
+	go func() {
+		var cmds []string
+		defer func() { received <- cmds }()
+		defer outW.Close()
+		defer inR.Close()
+		scanner := bufio.NewScanner(inR)
+		for scanner.Scan() {
+			cmd := scanner.Text()
+			cmds = append(cmds, cmd)
+			for _, line := range replies[cmd] {
+				io.WriteString(outW, line+"\n")
+			}
+			if cmd == last {
+				return
+			}
+		}
+	}()
+	return c, received
+}
+
+func TestClientHandshake(t *testing.T) {
+	c, received := script(t, map[string][]string{
+		"uci": {
+			"id name Test Engine", "id author Someone",
+			"option name Hash type spin default 16 min 1 max 64",
+			"option name Broken type",
+			"uciok",
+		},
+		"isready":    {"readyok"},
+		"ucinewgame": {"info string new game"},
+	}, "quit")
+	ctx := context.Background()
+	if err := c.Handshake(ctx); err != nil {
+		t.Fatalf("Handshake() = %v", err)
+	}
+	if c.Name != "Test Engine" || c.Author != "Someone" {
+		t.Errorf("Name, Author = %q, %q, expected %q, %q", c.Name, c.Author, "Test Engine", "Someone")
+	}
+	expected := map[string]Option{"Hash": {Name: "Hash", Type: Spin, Default: "16", Min: 1, Max: 64}}
+	if !reflect.DeepEqual(c.Options, expected) {
+		t.Errorf("Options = %+v, expected %+v", c.Options, expected)
+	}
+	if err := c.SetOption("Hash", "32"); err != nil {
+		t.Fatalf("SetOption() = %v", err)
+	}
+	if err := c.IsReady(ctx); err != nil {
+		t.Fatalf("IsReady() = %v", err)
+	}
+	if err := c.NewGame(ctx); err != nil {
+		t.Fatalf("NewGame() = %v", err)
+	}
+	c.Quit()
+	<-c.Done()
+
+	cmds := []string{"uci", "setoption name Hash value 32", "isready", "ucinewgame", "isready", "quit"}
+	if v := <-received; !reflect.DeepEqual(v, cmds) {
+		t.Errorf("received %q, expected %q", v, cmds)
+	}
+}
+
+func TestClientGo(t *testing.T) {
+	c, received := script(t, map[string][]string{
+		"go wtime 1000 btime 2000 winc 10 binc 10": {
+			"info depth 1 score cp 5 pv e2e4",
+			"info depth 2 score cp bad",
+			"info currmove d2d4 currmovenumber 2",
+			"info depth 2 score cp 15 wdl 300 600 100 pv d2d4 d7d5",
+			"info nodes 100",
+			"bestmove d2d4 ponder d7d5",
+		},
+		"go depth 3": {"info depth 1 score mate 1 pv e7e8q", "bestmove e7e8q"},
+	}, "go depth 3")
+	ctx := context.Background()
+
+	if err := c.Position("", []string{"e2e4", "e7e5"}); err != nil {
+		t.Fatalf("Position() = %v", err)
+	}
+	var infos []Info
+	bm, info, err := c.Go(ctx, GoParams{WTime: time.Second, BTime: 2 * time.Second, WInc: 10 * time.Millisecond, BInc: 10 * time.Millisecond},
+		func(i Info) { infos = append(infos, i) })
+	if err != nil {
+		t.Fatalf("Go() = %v", err)
+	}
+	if expected := (BestMove{Move: "d2d4", Ponder: "d7d5"}); bm != expected {
+		t.Errorf("Go() = %+v, expected %+v", bm, expected)
+	}
+	if info.Depth != 2 || info.Score.CP != 15 || !reflect.DeepEqual(info.PV, []string{"d2d4", "d7d5"}) {
+		t.Errorf("Go() info = %+v, expected the depth 2 info", info)
+	}
+	if len(infos) != 4 {
+		t.Errorf("onInfo called %d times, expected 4", len(infos))
+	}
+
+	if err := c.Position("8/4P3/8/8/8/8/k7/K7 w - - 0 1", nil); err != nil {
+		t.Fatalf("Position() = %v", err)
+	}
+	bm, info, err = c.Go(ctx, GoParams{Depth: 3}, nil)
+	if err != nil {
+		t.Fatalf("Go() = %v", err)
+	}
+	if bm.Move != "e7e8q" || !info.Score.IsMate {
+		t.Errorf("Go() = %+v, %+v, expected e7e8q with a mate score", bm, info)
+	}
+
+	// The engine has exited.
+	if _, _, err := c.Go(ctx, GoParams{Depth: 3}, nil); err == nil {
+		t.Errorf("Go() = nil, expected an error")
+	}
+	<-c.Done()
+	cmds := []string{
+		"position startpos moves e2e4 e7e5", "go wtime 1000 btime 2000 winc 10 binc 10",
+		"position fen 8/4P3/8/8/8/8/k7/K7 w - - 0 1", "go depth 3",
+	}
+	if v := <-received; !reflect.DeepEqual(v, cmds) {
+		t.Errorf("received %q, expected %q", v, cmds)
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	// An engine that never answers times out.
+	c, _ := script(t, map[string][]string{"uci": {"id name Slow"}}, "")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := c.Handshake(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Handshake() = %v, expected %v", err, context.DeadlineExceeded)
+	}
+
+	// An engine that exits while searching.
+	c, _ = script(t, map[string][]string{"go infinite": {"info depth 1"}}, "go infinite")
+	if _, _, err := c.Go(context.Background(), GoParams{Infinite: true}, nil); !errors.Is(err, ErrClosed) {
+		t.Errorf("Go() = %v, expected %v", err, ErrClosed)
+	}
+
+	// A malformed bestmove.
+	c, _ = script(t, map[string][]string{"go movetime 10": {"bestmove"}}, "go movetime 10")
+	if _, _, err := c.Go(context.Background(), GoParams{MoveTime: 10 * time.Millisecond}, nil); err == nil || errors.Is(err, ErrClosed) {
+		t.Errorf("Go() = %v, expected a malformed bestmove error", err)
+	}
+}
+
+func TestGoParamsString(t *testing.T) {
+	tests := []struct {
+		p        GoParams
+		expected string
+	}{
+		{GoParams{}, "go"},
+		{GoParams{Infinite: true}, "go infinite"},
+		{GoParams{Ponder: true, WTime: time.Second, BTime: time.Second}, "go ponder wtime 1000 btime 1000"},
+		{GoParams{WTime: time.Minute, BTime: 30 * time.Second, WInc: time.Second, BInc: time.Second, MovesToGo: 20}, "go wtime 60000 btime 30000 winc 1000 binc 1000 movestogo 20"},
+		{GoParams{Depth: 5, Nodes: 1000, MoveTime: 250 * time.Millisecond}, "go depth 5 nodes 1000 movetime 250"},
+	}
+	for i, test := range tests {
+		if v := test.p.String(); v != test.expected {
+			t.Errorf("[%d] String() = %q, expected %q", i, v, test.expected)
+		}
+	}
+}
+
+func TestClientPerft(t *testing.T) {
+	c, _ := script(t, map[string][]string{
+		"go perft 2": {"info string NNUE evaluation using nn.nnue", "a2a3: 20", "b1c3: 20", "", "Nodes searched: 40"},
+	}, "go perft 2")
+	counts, total, err := c.Perft(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("Perft() = %v", err)
+	}
+	if expected := map[string]uint64{"a2a3": 20, "b1c3": 20}; !reflect.DeepEqual(counts, expected) || total != 40 {
+		t.Errorf("Perft() = %v, %d, expected %v, 40", counts, total, expected)
+	}
+	// The engine has exited.
+	if _, _, err := c.Perft(context.Background(), 2); err == nil {
+		t.Errorf("Perft() = nil, expected an error")
+	}
+}
@@ -0,0 +1,488 @@
+package uci
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"chess"
+	"chess/book"
+	"chess/search"
+)
+
+var (
+	welcome       = `GopherChess by Jeremy Faller (jeremy.faller@gmail.com)`
+	ws            = " \t"
+	optionErr     = "No such option"
+	unknownCmdErr = "Unknown command"
+	numProcs      int
+)
+
+func init() {
+	numProcs = runtime.GOMAXPROCS(0)
+}
+
+// Engine runs the engine's search over UCI, reading commands from stdin and
+// writing to stdout.
+type Engine struct {
+	e   *search.Eval
+	b   *chess.Board
+	out io.Writer
+
+	bookSelector book.Selector
+	checkBoard   bool   // Check the board's invariants after every move.
+	hashFile     string // Where savehash saves the transposition table.
+}
+
+// NewEngine creates an Engine.
+func NewEngine(opts ...search.EvalOption) *Engine {
+	eval := search.NewEval(5, opts...)
+	eval.SetOutput(os.Stdout)
+	return &Engine{e: &eval, out: os.Stdout}
+}
+
+func trim(s string) string {
+	return strings.Trim(s, ws)
+}
+
+func removeBlanks(strs []string) []string {
+	ret := []string{}
+	for _, s := range strs {
+		if len(s) != 0 {
+			ret = append(ret, s)
+		}
+	}
+	return ret
+}
+
+func (u *Engine) Writeln(s string) {
+	fmt.Fprintln(u.out, s)
+}
+
+func (u *Engine) listOptions() {
+	u.Writeln("id name GopherChess")
+	u.Writeln("id author Jeremy Faller (jeremy.faller@gmail.com)")
+	u.Writeln("")
+	u.Writeln(fmt.Sprintf("option name Thread type spin default %d min 1 max %d", numProcs, numProcs))
+	u.Writeln("option name Book type check default true")
+	u.Writeln("option name BookFile type string default <empty>")
+	u.Writeln("option name BookMode type combo default Weighted var Best var Weighted var Uniform")
+	u.Writeln("option name BookMinWeight type spin default 0 min 0 max 100")
+	u.Writeln("option name BookDepth type spin default 0 min 0 max 1000")
+	u.Writeln("option name TranspositionMB type spin default 10 min 1 max 1000")
+	u.Writeln("option name HashFile type string default <empty>")
+	u.Writeln("option name Clear Hash type button")
+	u.Writeln("option name Ponder type check default false")
+	u.Writeln("option name Seed type spin default 0 min 0 max 2147483647")
+	u.Writeln("uciok")
+}
+
+func (u *Engine) isReady() {
+	// GUIs wait for readyok before searching, so it's the time to do slow
+	// setup.
+	u.e.LoadBook()
+	u.Writeln("readyok")
+}
+
+func (u *Engine) printError(str string, cmds []string) {
+	u.Writeln(fmt.Sprintf("%s: %q", str, strings.Join(cmds, " ")))
+}
+
+// parseSetOption splits the tokens of a setoption command, "name <id> [value <x>]",
+// into the option's name and value.
+func parseSetOption(tokens []string) (name, value string, ok bool) {
+	if len(tokens) < 2 || tokens[0] != "name" {
+		return "", "", false
+	}
+	i := slices.Index(tokens, "value")
+	if i < 0 {
+		return strings.Join(tokens[1:], " "), "", true
+	}
+	if i == 1 {
+		return "", "", false
+	}
+	return strings.Join(tokens[1:i], " "), strings.Join(tokens[i+1:], " "), true
+}
+
+func (u *Engine) setOption(tokens []string) {
+	name, value, ok := parseSetOption(tokens)
+	if !ok {
+		u.printError(optionErr, tokens)
+		return
+	}
+
+	switch name {
+	case "Thread":
+		if v, err := strconv.Atoi(value); err != nil {
+			u.printError(optionErr, tokens)
+		} else {
+			runtime.GOMAXPROCS(v)
+		}
+	case "Book":
+		if value == "true" {
+			u.e.SetBook(true)
+		} else if value == "false" {
+			u.e.SetBook(false)
+		} else {
+			u.printError(optionErr, tokens)
+		}
+	case "BookFile":
+		if err := u.loadBookFile(value); err != nil {
+			u.Writeln(fmt.Sprintf("%v", err))
+		}
+	case "BookMode":
+		if mode, err := book.ParseMode(value); err != nil {
+			u.printError(optionErr, tokens)
+		} else {
+			u.bookSelector.Mode = mode
+			u.e.SetBookSelector(u.bookSelector)
+		}
+	case "BookMinWeight":
+		if v, err := strconv.Atoi(value); err != nil || v < 0 || v > 100 {
+			u.printError(optionErr, tokens)
+		} else {
+			u.bookSelector.MinWeight = v
+			u.e.SetBookSelector(u.bookSelector)
+		}
+	case "BookDepth":
+		if v, err := strconv.Atoi(value); err != nil || v < 0 {
+			u.printError(optionErr, tokens)
+		} else {
+			u.bookSelector.MaxPly = v
+			u.e.SetBookSelector(u.bookSelector)
+		}
+	case "TranspositionMB":
+		if v, err := strconv.Atoi(value); err != nil || v < 0 {
+			u.printError(optionErr, tokens)
+		} else {
+			u.e.SetTranspositionTableSize(v)
+		}
+	case "Clear Hash":
+		u.e.ClearHash()
+	case "Ponder":
+		// We can always ponder, and don't budget our time differently if we
+		// will, so there's nothing to set.
+		if value != "true" && value != "false" {
+			u.printError(optionErr, tokens)
+		}
+	case "HashFile":
+		if err := u.loadHashFile(value); err != nil {
+			u.Writeln(fmt.Sprintf("%v", err))
+		}
+	case "Seed":
+		if v, err := strconv.ParseInt(value, 10, 64); err != nil || v < 0 {
+			u.printError(optionErr, tokens)
+		} else {
+			u.e.SetSeed(v)
+		}
+	default:
+		u.printError(optionErr, tokens)
+	}
+}
+
+// loadBookFile loads an opening book. An empty path restores the built-in book.
+func (u *Engine) loadBookFile(path string) error {
+	if len(path) == 0 || path == "<empty>" {
+		u.e.SetOpeningBook(nil)
+		return nil
+	}
+	ob, err := book.LoadFile(path)
+	if err != nil {
+		return err
+	}
+	u.e.SetOpeningBook(ob)
+	return nil
+}
+
+// position sets the position for the chess engine.
+func (u *Engine) position(cmd string) error {
+	// Split up the string.
+	var moves []string
+	fen, moveStr, found := strings.Cut(cmd, "moves")
+	if found {
+		moves = strings.Fields(moveStr)
+	}
+
+	// Get the starting position.
+	fen = trim(fen)
+	if fen == "startpos" {
+		fen = chess.StartingFEN
+	}
+	b, err := chess.FromFEN(fen)
+	if err != nil {
+		return err
+	}
+	b.SetDebug(u.checkBoard)
+
+	// Apply the moves, keeping the old position if any of them are bad.
+	if err := b.ApplyMoves(moves); err != nil {
+		return err
+	}
+	u.b = b
+	return nil
+}
+
+// loadHashFile sets the file the transposition table is saved to, and loads
+// it if it exists.
+func (u *Engine) loadHashFile(path string) error {
+	if path == "<empty>" {
+		path = ""
+	}
+	u.hashFile = path
+	if len(path) == 0 {
+		return nil
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := u.e.LoadTranspositionTable(f); err != nil {
+		return fmt.Errorf("error loading %q: %w", path, err)
+	}
+	return nil
+}
+
+// saveHash saves the transposition table to the given file, or the HashFile
+// if none is given. It writes a temporary file first, so a failed save
+// doesn't clobber an earlier one.
+func (u *Engine) saveHash(opts []string) error {
+	path := u.hashFile
+	if len(opts) > 0 {
+		path = strings.Join(opts, " ")
+	}
+	if len(path) == 0 {
+		return errors.New("no HashFile set")
+	}
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if err := u.e.SaveTranspositionTable(f); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// newgame creates a new game, resetting the search so it doesn't depend on
+// earlier games. A HashFile is reloaded, as it's set to carry the table over.
+func (u *Engine) newGame() error {
+	u.e.NewGame()
+	if err := u.loadHashFile(u.hashFile); err != nil {
+		return err
+	}
+	return u.position("startpos")
+}
+
+func (u *Engine) goCmd(cmd string) error {
+	kind, opts, _ := strings.Cut(cmd, " ")
+	switch kind {
+	case "perft":
+		res := strings.SplitN(trim(opts), ws, 2)
+		cnt, err := strconv.Atoi(res[0])
+		if err != nil {
+			return fmt.Errorf("no perft count specified")
+		}
+		start := time.Now()
+		nodes := u.b.PerftParallel(cnt, 0, 0, func(m chess.Move, cnt uint64) {
+			u.Writeln(fmt.Sprintf("%v: %d", m.UCIString(), cnt))
+		})
+		d := time.Since(start)
+		u.Writeln(fmt.Sprintf("\nTime: %v, %.2f Mnps", d.Round(time.Millisecond), float64(nodes)/d.Seconds()/1e6))
+		u.Writeln(fmt.Sprintf("Nodes searched: %d\n", nodes))
+		return nil
+	}
+
+	// Stop any search before changing its duration.
+	u.e.Stop()
+	d, ponder, err := u.searchTime(removeBlanks(strings.Split(cmd, " ")))
+	if err != nil {
+		return err
+	}
+	u.e.SetDuration(d)
+
+	// Search a copy, so commands read while searching can't change the board.
+	if ponder {
+		u.e.Ponder(u.b.Copy())
+	} else {
+		u.e.Start(u.b.Copy())
+	}
+	return nil
+}
+
+// searchTime works out how long to search from the parameters of a go
+// command, and whether to ponder. Without a movetime, we budget an even share
+// of our clock over the moves to go (or 30 if not given), plus half our
+// increment. A duration of 0 searches until done or stopped.
+func (u *Engine) searchTime(tokens []string) (d time.Duration, ponder bool, err error) {
+	var moveTime, clock, inc time.Duration
+	movesToGo := 30
+	white := u.b.Turn() == chess.White
+	for i := 0; i < len(tokens); i++ {
+		switch tokens[i] {
+		case "ponder":
+			ponder = true
+		case "infinite":
+		case "wtime", "btime", "winc", "binc", "movestogo", "movetime":
+			if i+1 == len(tokens) {
+				return 0, false, fmt.Errorf("no value for %s", tokens[i])
+			}
+			v, err := strconv.Atoi(tokens[i+1])
+			if err != nil || v < 0 {
+				return 0, false, fmt.Errorf("bad %s: %q", tokens[i], tokens[i+1])
+			}
+			ms := time.Duration(v) * time.Millisecond
+			switch tokens[i] {
+			case "wtime", "btime":
+				if white == (tokens[i] == "wtime") {
+					clock = ms
+				}
+			case "winc", "binc":
+				if white == (tokens[i] == "winc") {
+					inc = ms
+				}
+			case "movestogo":
+				movesToGo = max(v, 1)
+			case "movetime":
+				moveTime = ms
+			}
+			i++
+		default:
+			return 0, false, fmt.Errorf("unsupported go parameter: %q", tokens[i])
+		}
+	}
+
+	switch {
+	case moveTime != 0:
+		return moveTime, ponder, nil
+	case clock != 0:
+		d := min(clock/time.Duration(movesToGo)+inc/2, clock/2)
+		return max(d, time.Millisecond), ponder, nil
+	}
+	return 0, ponder, nil
+}
+
+func (u *Engine) stopCmd() {
+	u.e.Stop()
+}
+
+func (u *Engine) debug(opts []string) {
+	if len(opts) != 1 {
+		u.printError(unknownCmdErr, opts)
+		return
+	}
+	switch opts[0] {
+	case "on":
+		u.e.SetDebug(true)
+		u.checkBoard = true
+	case "off":
+		u.e.SetDebug(false)
+		u.checkBoard = false
+	default:
+		u.printError(unknownCmdErr, opts)
+		return
+	}
+	u.b.SetDebug(u.checkBoard)
+}
+
+// Run runs the engine on stdin and stdout, until it reads "quit" or stdin is
+// closed.
+func (u *Engine) Run() error {
+	return u.run(os.Stdin, os.Stdout)
+}
+
+// run runs the engine, reading commands from r and writing to w. Commands
+// are read while searching, so a search can be stopped, and isready
+// answered, at any time. Any search is stopped before returning.
+func (u *Engine) run(r io.Reader, w io.Writer) error {
+	u.out = w
+	u.e.SetOutput(w)
+	if err := u.newGame(); err != nil {
+		panic(err)
+	}
+	u.Writeln(welcome)
+
+	lines := make(chan string)
+	quit := make(chan struct{})
+	errc := make(chan error, 1)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			select {
+			case lines <- scanner.Text():
+			case <-quit:
+				return
+			}
+		}
+		errc <- scanner.Err()
+	}()
+	defer close(quit)
+	defer u.e.Stop()
+
+	for line := range lines {
+		if u.command(line) {
+			return nil
+		}
+	}
+	return <-errc
+}
+
+// command runs a single command, and returns true if it was "quit".
+func (u *Engine) command(line string) (quit bool) {
+	var err error
+	str := trim(line)
+	if len(str) == 0 { // Skip blanks.
+		return false
+	}
+
+	strs := removeBlanks(strings.Split(str, " "))
+	cmdStripped := trim(strings.TrimPrefix(str, strs[0]))
+	switch strs[0] {
+	case "debug":
+		u.debug(strs[1:])
+	case "isready":
+		u.isReady()
+	case "go":
+		err = u.goCmd(cmdStripped)
+	case "ponderhit":
+		u.e.PonderHit()
+	case "position":
+		err = u.position(cmdStripped)
+	case "quit":
+		return true
+	case "savehash":
+		err = u.saveHash(strs[1:])
+	case "setoption":
+		u.setOption(strs[1:])
+	case "stop":
+		u.stopCmd()
+	case "uci":
+		u.listOptions()
+	case "ucinewgame":
+		err = u.newGame()
+	default:
+		u.printError(unknownCmdErr, strs)
+	}
+
+	if err != nil {
+		u.Writeln(fmt.Sprintf("%v", err))
+	}
+	return false
+}
@@ -0,0 +1,179 @@
+package uci
+
+import (
+	"bufio"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// runEngine runs an Engine over pipes. It returns a func to send commands, the
+// lines the engine writes, and the result of its run.
+func runEngine(t *testing.T) (func(string), <-chan string, <-chan error) {
+	t.Helper()
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	u := NewEngine()
+	u.e.SetBook(false)
+	errc := make(chan error, 1)
+	go func() {
+		errc <- u.run(inR, outW)
+		outW.Close()
+	}()
+	lines := make(chan string, 100)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(outR)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+	t.Cleanup(func() { inW.Close() })
+	if line := readLine(t, lines); line != welcome {
+		t.Fatalf("engine wrote %q, expected %q", line, welcome)
+	}
+	send := func(cmd string) {
+		if cmd == "" {
+			inW.Close()
+		} else {
+			io.WriteString(inW, cmd+"\n")
+		}
+	}
+	return send, lines, errc
+}
+
+// readLine returns the next line the engine writes, failing if it takes too
+// long.
+func readLine(t *testing.T, lines <-chan string) string {
+	t.Helper()
+	select {
+	case line, ok := <-lines:
+		if !ok {
+			t.Fatalf("engine output closed")
+		}
+		return line
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for the engine")
+	}
+	return ""
+}
+
+func TestEngineStopAndQuit(t *testing.T) {
+	hashFile := filepath.Join(t.TempDir(), "hash")
+	tests := []struct {
+		cmds     []string // "" closes the engine's input.
+		expected []string // Prefixes of the lines the engine writes.
+	}{
+		// isready is answered while pondering, and stop reports exactly once.
+		{
+			[]string{"go ponder", "isready", "stop", "stop", "isready", "quit"},
+			[]string{"readyok", "bestmove ", "readyok"},
+		},
+		// A finished search isn't reported again by stop.
+		{
+			[]string{"go movetime 10", "isready", "stop", "isready", "quit"},
+			[]string{"bestmove ", "readyok", "readyok"},
+		},
+		// quit stops a search.
+		{
+			[]string{"go infinite", "quit"},
+			[]string{"bestmove "},
+		},
+		// So does closing the input.
+		{
+			[]string{"go ponder", ""},
+			[]string{"bestmove "},
+		},
+		// Commands that change the transposition table stop a search first.
+		{
+			[]string{"go infinite", "setoption name Clear Hash", "isready", "quit"},
+			[]string{"bestmove ", "readyok"},
+		},
+		{
+			[]string{"go ponder", "setoption name TranspositionMB value 1", "isready", "quit"},
+			[]string{"bestmove ", "readyok"},
+		},
+		{
+			[]string{"go infinite", "savehash " + hashFile, "isready", "quit"},
+			[]string{"bestmove ", "readyok"},
+		},
+	}
+
+	for i, test := range tests {
+		send, lines, errc := runEngine(t)
+		for _, cmd := range test.cmds {
+			send(cmd)
+			if cmd == "isready" || cmd == "go movetime 10" {
+				// Let the search finish, or check it hasn't been reported.
+				time.Sleep(50 * time.Millisecond)
+			}
+		}
+		select {
+		case err := <-errc:
+			if err != nil {
+				t.Errorf("[%d] run() = %v", i, err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("[%d] engine didn't exit", i)
+		}
+		var out []string
+		for line := range lines {
+			out = append(out, line)
+		}
+		if len(out) != len(test.expected) {
+			t.Errorf("[%d] engine wrote %q, expected %q", i, out, test.expected)
+			continue
+		}
+		for j := range out {
+			if !strings.HasPrefix(out[j], test.expected[j]) {
+				t.Errorf("[%d] engine wrote %q, expected %q", i, out, test.expected)
+				break
+			}
+		}
+	}
+}
+
+func TestEnginePosition(t *testing.T) {
+	tests := []struct {
+		cmds     []string // Arguments to position, all but the last of which are good.
+		bad      bool     // True if the last one is bad.
+		expected string
+	}{
+		{
+			[]string{"startpos moves e2e4 e7e5 g1f3"},
+			false,
+			"rnbqkbnr/pppp1ppp/8/4p3/4P3/5N2/PPPP1PPP/RNBQKB1R b KQkq - 1 2",
+		},
+		{
+			[]string{"startpos moves  e2e4\te7e5 g1f3 "},
+			false,
+			"rnbqkbnr/pppp1ppp/8/4p3/4P3/5N2/PPPP1PPP/RNBQKB1R b KQkq - 1 2",
+		},
+		// A bad move or FEN leaves the previous position.
+		{
+			[]string{"startpos moves e2e4", "startpos moves d2d4 e2e5"},
+			true,
+			"rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1",
+		},
+		{
+			[]string{"startpos moves e2e4", "8/8/8 w - - 0 1"},
+			true,
+			"rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1",
+		},
+	}
+
+	for i, test := range tests {
+		u := NewEngine()
+		for j, cmd := range test.cmds {
+			bad := test.bad && j == len(test.cmds)-1
+			if err := u.position(cmd); (err != nil) != bad {
+				t.Errorf("[%d] position(%q) = %v, expected error %t", i, cmd, err, bad)
+			}
+		}
+		if fen := u.b.FENString(); fen != test.expected {
+			t.Errorf("[%d] board = %q, expected %q", i, fen, test.expected)
+		}
+	}
+}
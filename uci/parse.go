@@ -0,0 +1,286 @@
+// Package uci implements the Universal Chess Interface: the engine side, which
+// runs the search for a GUI or match runner, and the client side, for programs
+// that drive UCI engines.
+//
+// https://www.wbec-ridderkerk.nl/html/UCIProtocol.html
+package uci
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OptionType is the type of an engine's option.
+type OptionType string
+
+const (
+	Check  OptionType = "check"
+	Spin   OptionType = "spin"
+	Combo  OptionType = "combo"
+	Button OptionType = "button"
+	String OptionType = "string"
+)
+
+// Option is an option an engine supports.
+type Option struct {
+	Name     string
+	Type     OptionType
+	Default  string
+	Min, Max int      // Bounds of spin options.
+	Vars     []string // Values of combo options.
+}
+
+// ParseOption parses an "option" line.
+func ParseOption(line string) (Option, error) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 || fields[0] != "option" {
+		return Option{}, fmt.Errorf("not an option: %q", line)
+	}
+
+	// Values can have spaces, so gather the words following each keyword.
+	var opt Option
+	var key string
+	values := make(map[string][]string)
+	for _, f := range fields[1:] {
+		switch f {
+		case "name", "type", "default", "min", "max":
+			key = f
+			values[key] = []string{}
+			continue
+		case "var":
+			key = f
+			opt.Vars = append(opt.Vars, "")
+			continue
+		}
+		switch key {
+		case "":
+			return Option{}, fmt.Errorf("malformed option: %q", line)
+		case "var":
+			v := &opt.Vars[len(opt.Vars)-1]
+			*v = strings.TrimPrefix(*v+" "+f, " ")
+		default:
+			values[key] = append(values[key], f)
+		}
+	}
+
+	opt.Name = strings.Join(values["name"], " ")
+	opt.Type = OptionType(strings.Join(values["type"], " "))
+	opt.Default = strings.Join(values["default"], " ")
+	if len(opt.Name) == 0 || len(opt.Type) == 0 {
+		return Option{}, fmt.Errorf("malformed option: %q", line)
+	}
+	switch opt.Type {
+	case Check, Combo, Button, String:
+	case Spin:
+		var err error
+		if opt.Min, err = strconv.Atoi(strings.Join(values["min"], "")); err != nil {
+			return Option{}, fmt.Errorf("malformed spin option min: %q", line)
+		}
+		if opt.Max, err = strconv.Atoi(strings.Join(values["max"], "")); err != nil {
+			return Option{}, fmt.Errorf("malformed spin option max: %q", line)
+		}
+	default:
+		return Option{}, fmt.Errorf("unknown option type: %q", opt.Type)
+	}
+	return opt, nil
+}
+
+// Score is an engine's evaluation of a position, from the side to move's
+// point of view.
+type Score struct {
+	CP         int  // Centipawns, if not a mate score.
+	Mate       int  // Moves until mate, negative if the engine is getting mated.
+	IsMate     bool // True if the score is a mate score.
+	LowerBound bool
+	UpperBound bool
+}
+
+// String returns the score as it's written by UCI, eg "cp 25" or "mate -3".
+func (s Score) String() string {
+	var str string
+	if s.IsMate {
+		str = fmt.Sprintf("mate %d", s.Mate)
+	} else {
+		str = fmt.Sprintf("cp %d", s.CP)
+	}
+	if s.LowerBound {
+		str += " lowerbound"
+	} else if s.UpperBound {
+		str += " upperbound"
+	}
+	return str
+}
+
+// Info is the search information in an "info" line. Fields the engine didn't
+// send are left as their zero values.
+type Info struct {
+	Depth          int
+	SelDepth       int
+	MultiPV        int
+	Score          Score
+	HasScore       bool
+	Nodes          int64
+	NPS            int64
+	Time           time.Duration
+	HashFull       int // Permill.
+	TBHits         int64
+	CurrMove       string
+	CurrMoveNumber int
+	PV             []string
+	String         string
+}
+
+// infoKeywords are the keywords that start the fields of an info line.
+var infoKeywords = map[string]bool{
+	"depth": true, "seldepth": true, "time": true, "nodes": true, "pv": true,
+	"multipv": true, "score": true, "currmove": true, "currmovenumber": true,
+	"hashfull": true, "nps": true, "tbhits": true, "sbhits": true, "cpuload": true,
+	"string": true, "refutation": true, "currline": true,
+}
+
+// ParseInfo parses an "info" line.
+func ParseInfo(line string) (Info, error) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 || fields[0] != "info" {
+		return Info{}, fmt.Errorf("not an info line: %q", line)
+	}
+
+	var info Info
+	var err error
+	errorf := func(format string, args ...any) error {
+		return fmt.Errorf("malformed info %q: %s", line, fmt.Sprintf(format, args...))
+	}
+	next := func(i *int) (string, error) {
+		if *i+1 >= len(fields) {
+			return "", errorf("missing value for %v", fields[*i])
+		}
+		*i += 1
+		return fields[*i], nil
+	}
+	nextInt := func(i *int) (int64, error) {
+		key := fields[*i]
+		s, err := next(i)
+		if err != nil {
+			return 0, err
+		}
+		v, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return 0, errorf("invalid %v: %q", key, s)
+		}
+		return v, nil
+	}
+
+	for i := 1; i < len(fields) && err == nil; i++ {
+		var v int64
+		switch fields[i] {
+		case "depth":
+			v, err = nextInt(&i)
+			info.Depth = int(v)
+		case "seldepth":
+			v, err = nextInt(&i)
+			info.SelDepth = int(v)
+		case "multipv":
+			v, err = nextInt(&i)
+			info.MultiPV = int(v)
+		case "time":
+			v, err = nextInt(&i)
+			info.Time = time.Duration(v) * time.Millisecond
+		case "nodes":
+			info.Nodes, err = nextInt(&i)
+		case "nps":
+			info.NPS, err = nextInt(&i)
+		case "tbhits":
+			info.TBHits, err = nextInt(&i)
+		case "hashfull":
+			v, err = nextInt(&i)
+			info.HashFull = int(v)
+		case "currmovenumber":
+			v, err = nextInt(&i)
+			info.CurrMoveNumber = int(v)
+		case "sbhits", "cpuload":
+			_, err = nextInt(&i)
+		case "currmove":
+			info.CurrMove, err = next(&i)
+		case "score":
+			err = parseScore(fields, &i, &info.Score)
+			info.HasScore = err == nil
+			if err != nil {
+				err = errorf("%v", err)
+			}
+		case "pv":
+			for i+1 < len(fields) && !infoKeywords[fields[i+1]] {
+				i += 1
+				info.PV = append(info.PV, fields[i])
+			}
+		case "string":
+			info.String = strings.Join(fields[i+1:], " ")
+			i = len(fields)
+		default:
+			// Skip fields we don't use, like refutation, and ones engines add
+			// to the protocol, like wdl, up to the next field we know.
+			for i+1 < len(fields) && !infoKeywords[fields[i+1]] {
+				i += 1
+			}
+		}
+	}
+	if err != nil {
+		return Info{}, err
+	}
+	return info, nil
+}
+
+// parseScore parses the fields after "score", leaving i at the last field
+// used.
+func parseScore(fields []string, i *int, s *Score) error {
+	if *i+2 >= len(fields) {
+		return errors.New("missing score")
+	}
+	v, err := strconv.Atoi(fields[*i+2])
+	if err != nil {
+		return fmt.Errorf("invalid score: %q", fields[*i+2])
+	}
+	switch fields[*i+1] {
+	case "cp":
+		s.CP = v
+	case "mate":
+		s.Mate, s.IsMate = v, true
+	default:
+		return fmt.Errorf("invalid score type: %q", fields[*i+1])
+	}
+	*i += 2
+	if *i+1 < len(fields) {
+		switch fields[*i+1] {
+		case "lowerbound":
+			s.LowerBound = true
+			*i += 1
+		case "upperbound":
+			s.UpperBound = true
+			*i += 1
+		}
+	}
+	return nil
+}
+
+// BestMove is the result of a search.
+type BestMove struct {
+	Move   string
+	Ponder string // The move the engine expects in reply, if it sent one.
+}
+
+// ParseBestMove parses a "bestmove" line.
+func ParseBestMove(line string) (BestMove, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 2 || fields[0] != "bestmove" {
+		return BestMove{}, fmt.Errorf("malformed bestmove: %q", line)
+	}
+	bm := BestMove{Move: fields[1]}
+	if len(fields) >= 4 && fields[2] == "ponder" {
+		bm.Ponder = fields[3]
+	} else if len(fields) != 2 {
+		return BestMove{}, fmt.Errorf("malformed bestmove: %q", line)
+	}
+	return bm, nil
+}
@@ -0,0 +1,126 @@
+package uci
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseOption(t *testing.T) {
+	tests := []struct {
+		line     string
+		expected Option
+		isErr    bool
+	}{
+		{"option name Hash type spin default 16 min 1 max 1024", Option{Name: "Hash", Type: Spin, Default: "16", Min: 1, Max: 1024}, false},
+		{"option name Clear Hash type button", Option{Name: "Clear Hash", Type: Button}, false},
+		{"option name Ponder type check default false", Option{Name: "Ponder", Type: Check, Default: "false"}, false},
+		{"option name Book File type string default my book.bin", Option{Name: "Book File", Type: String, Default: "my book.bin"}, false},
+		{"option name Style type combo default Normal var Solid var Normal var Very Risky", Option{Name: "Style", Type: Combo, Default: "Normal", Vars: []string{"Solid", "Normal", "Very Risky"}}, false},
+		{"option name Hash type spin default 16", Option{}, true},
+		{"option name Hash type spin default 16 min one max 1024", Option{}, true},
+		{"option type check default false", Option{}, true},
+		{"option name Foo type bar", Option{}, true},
+		{"option Foo name Hash type check", Option{}, true},
+		{"id name Foo", Option{}, true},
+	}
+	for i, test := range tests {
+		opt, err := ParseOption(test.line)
+		if isErr := err != nil; isErr != test.isErr {
+			t.Errorf("[%d] ParseOption(%q) = %v, expected error %v", i, test.line, err, test.isErr)
+			continue
+		}
+		if !reflect.DeepEqual(opt, test.expected) {
+			t.Errorf("[%d] ParseOption(%q) = %+v, expected %+v", i, test.line, opt, test.expected)
+		}
+	}
+}
+
+func TestParseInfo(t *testing.T) {
+	tests := []struct {
+		line     string
+		expected Info
+		isErr    bool
+	}{
+		{"info", Info{}, false},
+		{
+			"info depth 12 seldepth 18 multipv 1 score cp -25 nodes 123456 nps 1000000 hashfull 42 tbhits 0 time 123 pv e2e4 e7e5 g1f3",
+			Info{Depth: 12, SelDepth: 18, MultiPV: 1, Score: Score{CP: -25}, HasScore: true, Nodes: 123456, NPS: 1000000,
+				HashFull: 42, Time: 123 * time.Millisecond, PV: []string{"e2e4", "e7e5", "g1f3"}},
+			false,
+		},
+		{"info score mate -3 upperbound depth 5", Info{Score: Score{Mate: -3, IsMate: true, UpperBound: true}, HasScore: true, Depth: 5}, false},
+		{"info depth 5 score cp 10 lowerbound", Info{Score: Score{CP: 10, LowerBound: true}, HasScore: true, Depth: 5}, false},
+		{"info pv d2d4 d7d5 depth 3", Info{PV: []string{"d2d4", "d7d5"}, Depth: 3}, false},
+		{"info currmove e2e4 currmovenumber 1", Info{CurrMove: "e2e4", CurrMoveNumber: 1}, false},
+		{"info depth 2 string hello depth 3", Info{Depth: 2, String: "hello depth 3"}, false},
+		{"info refutation d1h5 g6h5 depth 2", Info{Depth: 2}, false},
+		{"info depth", Info{}, true},
+		{"info depth x", Info{}, true},
+		{"info score", Info{}, true},
+		{"info score cp", Info{}, true},
+		{"info score foo 10", Info{}, true},
+		{"info score cp ten", Info{}, true},
+		{"info foo 10", Info{}, false},
+		{
+			"info depth 8 score cp 31 wdl 120 820 60 nodes 5000 pv e2e4",
+			Info{Depth: 8, Score: Score{CP: 31}, HasScore: true, Nodes: 5000, PV: []string{"e2e4"}},
+			false,
+		},
+		{"info wdl 0 0 1000 score mate 2", Info{Score: Score{Mate: 2, IsMate: true}, HasScore: true}, false},
+		{"bestmove e2e4", Info{}, true},
+	}
+	for i, test := range tests {
+		info, err := ParseInfo(test.line)
+		if isErr := err != nil; isErr != test.isErr {
+			t.Errorf("[%d] ParseInfo(%q) = %v, expected error %v", i, test.line, err, test.isErr)
+			continue
+		}
+		if !reflect.DeepEqual(info, test.expected) {
+			t.Errorf("[%d] ParseInfo(%q) = %+v, expected %+v", i, test.line, info, test.expected)
+		}
+	}
+}
+
+func TestScoreString(t *testing.T) {
+	tests := []struct {
+		s        Score
+		expected string
+	}{
+		{Score{CP: 25}, "cp 25"},
+		{Score{Mate: -3, IsMate: true}, "mate -3"},
+		{Score{CP: 10, LowerBound: true}, "cp 10 lowerbound"},
+		{Score{CP: 10, UpperBound: true}, "cp 10 upperbound"},
+	}
+	for i, test := range tests {
+		if v := test.s.String(); v != test.expected {
+			t.Errorf("[%d] String() = %q, expected %q", i, v, test.expected)
+		}
+	}
+}
+
+func TestParseBestMove(t *testing.T) {
+	tests := []struct {
+		line     string
+		expected BestMove
+		isErr    bool
+	}{
+		{"bestmove e2e4", BestMove{Move: "e2e4"}, false},
+		{"bestmove e7e8q ponder a2a1n", BestMove{Move: "e7e8q", Ponder: "a2a1n"}, false},
+		{"bestmove (none)", BestMove{Move: "(none)"}, false},
+		{"bestmove", BestMove{}, true},
+		{"bestmove: e2e4", BestMove{}, true},
+		{"bestmove e2e4 ponder", BestMove{}, true},
+		{"bestmove e2e4 e7e5", BestMove{}, true},
+	}
+	for i, test := range tests {
+		bm, err := ParseBestMove(test.line)
+		if isErr := err != nil; isErr != test.isErr {
+			t.Errorf("[%d] ParseBestMove(%q) = %v, expected error %v", i, test.line, err, test.isErr)
+			continue
+		}
+		if bm != test.expected {
+			t.Errorf("[%d] ParseBestMove(%q) = %+v, expected %+v", i, test.line, bm, test.expected)
+		}
+	}
+}
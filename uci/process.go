@@ -0,0 +1,81 @@
+package uci
+
+import (
+	"fmt"
+	"os/exec"
+	"time"
+)
+
+// quitTimeout is how long an engine has to exit after quit before it's killed.
+const quitTimeout = time.Second
+
+// Process is a UCI engine running as a subprocess, with a Client talking to it
+// over its stdin and stdout.
+type Process struct {
+	*Client
+	cmd    *exec.Cmd
+	close  func() error  // Closes the engine's stdin.
+	exited chan struct{} // Closed when the engine has exited.
+}
+
+// StartProcess starts cmd as a UCI engine. The command's stdin and stdout are
+// connected to the Process's Client, and anything else about it, like its
+// stderr or directory, is left as the caller set it.
+func StartProcess(cmd *exec.Cmd) (*Process, error) {
+	if cmd.Err != nil {
+		return nil, fmt.Errorf("error finding executable: %w", cmd.Err)
+	}
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("error attaching to stdin: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("error attaching to stdout: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("error starting %v: %w", cmd.Path, err)
+	}
+	p := &Process{
+		Client: NewClient(stdout, stdin),
+		cmd:    cmd,
+		close:  stdin.Close,
+		exited: make(chan struct{}),
+	}
+
+	// Reap the engine once its output has closed.
+	go func() {
+		<-p.Done()
+		cmd.Wait()
+		close(p.exited)
+	}()
+	return p, nil
+}
+
+// Path returns the path of the engine's executable.
+func (p *Process) Path() string {
+	return p.cmd.Path
+}
+
+// Exited returns true if the engine has exited.
+func (p *Process) Exited() bool {
+	select {
+	case <-p.exited:
+		return true
+	default:
+		return false
+	}
+}
+
+// Close asks the engine to quit, killing it if it doesn't exit in time, and
+// waits for it to exit.
+func (p *Process) Close() {
+	p.Quit()
+	p.close()
+	select {
+	case <-time.After(quitTimeout):
+		p.cmd.Process.Kill()
+		<-p.exited
+	case <-p.exited:
+	}
+}
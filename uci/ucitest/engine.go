@@ -0,0 +1,227 @@
+// Package ucitest provides a scriptable fake UCI engine, for testing programs
+// that drive engines without needing real ones.
+//
+// An Engine can be run in-process over pipes with Run, or as a subprocess by
+// having a test binary re-execute itself (see Main).
+package ucitest
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"chess"
+	"chess/uci"
+)
+
+// Fault is a way an Engine can misbehave when asked for a move.
+type Fault string
+
+const (
+	// Illegal replies with a move that's never legal.
+	Illegal Fault = "illegal"
+
+	// Hang never replies, and ignores every command that follows.
+	Hang Fault = "hang"
+
+	// Crash exits without replying.
+	Crash Fault = "crash"
+
+	// BadInfo sends malformed info lines before replying normally.
+	BadInfo Fault = "badinfo"
+)
+
+// IllegalMove is the move sent for an Illegal fault.
+const IllegalMove = "a1a1"
+
+// ErrCrash is returned by Run for a Crash fault.
+var ErrCrash = errors.New("ucitest: engine crashed")
+
+// Engine is a scripted UCI engine. Its moves and faults are keyed by the ply
+// of the position it's asked to search, so the same Engine can play either
+// color. Unscripted positions get the first legal move.
+type Engine struct {
+	Name    string
+	Options []uci.Option
+	Moves   map[int]string // Canned moves, in long algebraic notation.
+	Faults  map[int]Fault
+	Score   int           // Centipawns reported for every search, from the engine's point of view.
+	Mate    int           // If not 0, mate in Mate moves is reported instead of Score.
+	Delay   time.Duration // How long each search takes.
+}
+
+// Run plays e over a UCI connection, reading commands from r and writing
+// responses to w. It returns nil when it reads "quit" or r is closed.
+func (e *Engine) Run(r io.Reader, w io.Writer) error {
+	b := chess.New()
+	hung := false
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || hung {
+			continue
+		}
+		var err error
+		switch fields[0] {
+		case "uci":
+			name := e.Name
+			if len(name) == 0 {
+				name = "ucitest"
+			}
+			fmt.Fprintf(w, "id name %s\nid author ucitest\n", name)
+			for _, opt := range e.Options {
+				fmt.Fprintln(w, optionString(opt))
+			}
+			fmt.Fprintln(w, "uciok")
+		case "isready":
+			fmt.Fprintln(w, "readyok")
+		case "ucinewgame":
+			b = chess.New()
+		case "position":
+			b, err = position(fields[1:])
+		case "go":
+			switch fault := e.Faults[b.Ply()]; fault {
+			case Hang:
+				hung = true
+			case Crash:
+				return ErrCrash
+			default:
+				err = e.search(w, b, fault)
+			}
+		case "quit":
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// search replies to a "go" command.
+func (e *Engine) search(w io.Writer, b *chess.Board, fault Fault) error {
+	if fault == BadInfo {
+		fmt.Fprintln(w, "info depth")
+		fmt.Fprintln(w, "info score cp lots")
+		fmt.Fprintln(w, "info nonsense 1")
+	}
+	time.Sleep(e.Delay)
+	if fault == Illegal {
+		_, err := fmt.Fprintf(w, "bestmove %s\n", IllegalMove)
+		return err
+	}
+	move, ok := e.Moves[b.Ply()]
+	if !ok {
+		moves := b.PossibleMoves(nil)
+		if len(moves) == 0 {
+			move = "(none)"
+		} else {
+			move = moves[0].UCIString()
+		}
+	}
+	score := fmt.Sprintf("cp %d", e.Score)
+	if e.Mate != 0 {
+		score = fmt.Sprintf("mate %d", e.Mate)
+	}
+	_, err := fmt.Fprintf(w, "info depth 1 score %s nodes 1 pv %s\nbestmove %s\n", score, move, move)
+	return err
+}
+
+// Client runs e in-process, and returns a Client connected to it. The
+// returned func closes the engine's input, and waits for it to stop.
+func (e *Engine) Client() (*uci.Client, func()) {
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		e.Run(inR, outW)
+		inR.Close()
+		outW.Close()
+	}()
+	return uci.NewClient(outR, inW), func() {
+		inW.Close()
+		<-done
+	}
+}
+
+// position parses the arguments of a "position" command.
+func position(fields []string) (*chess.Board, error) {
+	var b *chess.Board
+	var err error
+	switch {
+	case len(fields) == 0:
+		return nil, errors.New("ucitest: missing position")
+	case fields[0] == "startpos":
+		b, fields = chess.New(), fields[1:]
+	case fields[0] == "fen" && len(fields) >= 7:
+		b, err = chess.FromFEN(strings.Join(fields[1:7], " "))
+		fields = fields[7:]
+	default:
+		return nil, fmt.Errorf("ucitest: malformed position: %q", strings.Join(fields, " "))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) != 0 && fields[0] == "moves" {
+		err = b.ApplyMoves(fields[1:])
+	}
+	return b, err
+}
+
+// optionString returns the "option" line for opt.
+func optionString(opt uci.Option) string {
+	s := fmt.Sprintf("option name %s type %s", opt.Name, opt.Type)
+	if opt.Type != uci.Button {
+		s += " default " + opt.Default
+	}
+	if opt.Type == uci.Spin {
+		s += fmt.Sprintf(" min %d max %d", opt.Min, opt.Max)
+	}
+	for _, v := range opt.Vars {
+		s += " var " + v
+	}
+	return s
+}
+
+// mainArg is the argument that tells Main to run an engine.
+const mainArg = "-ucitest.engine"
+
+// Command returns the path and arguments that run e as a subprocess of the
+// current test binary, which must call Main from its TestMain.
+func (e *Engine) Command() (string, []string, error) {
+	path, err := os.Executable()
+	if err != nil {
+		return "", nil, err
+	}
+	js, err := json.Marshal(e)
+	if err != nil {
+		return "", nil, err
+	}
+	return path, []string{mainArg, string(js)}, nil
+}
+
+// Main runs an engine over stdin and stdout, and exits, if the process was
+// started by a Command. Otherwise it returns, so a TestMain should start with
+//
+//	ucitest.Main()
+func Main() {
+	if len(os.Args) != 3 || os.Args[1] != mainArg {
+		return
+	}
+	var e Engine
+	if err := json.Unmarshal([]byte(os.Args[2]), &e); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := e.Run(os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	os.Exit(0)
+}
@@ -0,0 +1,175 @@
+package ucitest
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"reflect"
+	"testing"
+	"time"
+
+	"chess/uci"
+)
+
+func TestMain(m *testing.M) {
+	Main()
+	os.Exit(m.Run())
+}
+
+func TestProcess(t *testing.T) {
+	tests := []struct {
+		e     Engine
+		fault bool // The engine crashes or hangs on go.
+	}{
+		{Engine{Name: "Fake"}, false},
+		{Engine{Name: "Crash", Faults: map[int]Fault{0: Crash}}, true},
+		{Engine{Name: "Hang", Faults: map[int]Fault{0: Hang}}, true},
+	}
+	for i, test := range tests {
+		path, args, err := test.e.Command()
+		if err != nil {
+			t.Fatalf("[%d] Command() = %v", i, err)
+		}
+		p, err := uci.StartProcess(exec.Command(path, args...))
+		if err != nil {
+			t.Fatalf("[%d] StartProcess() = %v", i, err)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := p.Handshake(ctx); err != nil {
+			t.Fatalf("[%d] Handshake() = %v", i, err)
+		}
+		if p.Name != test.e.Name {
+			t.Errorf("[%d] Name = %q, expected %q", i, p.Name, test.e.Name)
+		}
+		p.Position("", nil)
+		ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		if _, _, err := p.Go(ctx, uci.GoParams{Depth: 1}, nil); (err != nil) != test.fault {
+			t.Errorf("[%d] Go() = %v", i, err)
+		}
+		if p.Exited() && !test.fault {
+			t.Errorf("[%d] Exited() = true before Close()", i)
+		}
+		p.Close()
+		if !p.Exited() {
+			t.Errorf("[%d] Exited() = false after Close()", i)
+		}
+	}
+
+	if _, err := uci.StartProcess(exec.Command("no-such-engine")); err == nil {
+		t.Errorf("StartProcess(no-such-engine) = nil, expected an error")
+	}
+}
+
+func TestEngineHandshake(t *testing.T) {
+	e := &Engine{
+		Name: "Fake",
+		Options: []uci.Option{
+			{Name: "Hash", Type: uci.Spin, Default: "16", Min: 1, Max: 64},
+			{Name: "Clear Hash", Type: uci.Button},
+			{Name: "Style", Type: uci.Combo, Default: "Normal", Vars: []string{"Solid", "Normal"}},
+		},
+	}
+	c, stop := e.Client()
+	defer stop()
+	ctx := context.Background()
+	if err := c.Handshake(ctx); err != nil {
+		t.Fatalf("Handshake() = %v", err)
+	}
+	if c.Name != "Fake" {
+		t.Errorf("Name = %q, expected %q", c.Name, "Fake")
+	}
+	for _, opt := range e.Options {
+		if v := c.Options[opt.Name]; !reflect.DeepEqual(v, opt) {
+			t.Errorf("Options[%q] = %+v, expected %+v", opt.Name, v, opt)
+		}
+	}
+	if err := c.NewGame(ctx); err != nil {
+		t.Errorf("NewGame() = %v", err)
+	}
+	c.Quit()
+	<-c.Done()
+}
+
+func TestEngineMoves(t *testing.T) {
+	e := &Engine{
+		Moves:  map[int]string{0: "e2e4", 2: "d2d4"},
+		Faults: map[int]Fault{1: BadInfo, 3: Illegal},
+		Score:  -30,
+	}
+	tests := []struct {
+		moves    []string
+		expected string
+	}{
+		{nil, "e2e4"},
+		{[]string{"e2e4"}, "a7a5"}, // The first legal move, after bad info.
+		{[]string{"e2e4", "a7a5"}, "d2d4"},
+		{[]string{"e2e4", "a7a5", "d2d4"}, IllegalMove},
+	}
+	c, stop := e.Client()
+	defer stop()
+	for i, test := range tests {
+		if err := c.Position("", test.moves); err != nil {
+			t.Fatalf("[%d] Position() = %v", i, err)
+		}
+		bm, info, err := c.Go(context.Background(), uci.GoParams{Depth: 1}, nil)
+		if err != nil {
+			t.Fatalf("[%d] Go() = %v", i, err)
+		}
+		if bm.Move != test.expected {
+			t.Errorf("[%d] Go() = %v, expected %v", i, bm.Move, test.expected)
+		}
+		if test.expected != IllegalMove && (!info.HasScore || info.Score.CP != e.Score) {
+			t.Errorf("[%d] Go() info = %+v, expected score %d", i, info, e.Score)
+		}
+	}
+
+	// Positions from a FEN are keyed by their ply too.
+	if err := c.Position("rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq - 0 1", []string{"e7e5"}); err != nil {
+		t.Fatalf("Position() = %v", err)
+	}
+	if bm, _, err := c.Go(context.Background(), uci.GoParams{Depth: 1}, nil); err != nil || bm.Move != "d2d4" {
+		t.Errorf("Go() = %v, %v, expected d2d4", bm.Move, err)
+	}
+}
+
+func TestEngineFaults(t *testing.T) {
+	// A crash closes the engine's output.
+	e := &Engine{Faults: map[int]Fault{0: Crash}}
+	c, stop := e.Client()
+	c.Position("", nil)
+	if _, _, err := c.Go(context.Background(), uci.GoParams{Depth: 1}, nil); !errors.Is(err, uci.ErrClosed) {
+		t.Errorf("Go() = %v, expected %v", err, uci.ErrClosed)
+	}
+	stop()
+
+	// A hung engine times out, and doesn't answer anything else.
+	e = &Engine{Faults: map[int]Fault{0: Hang}}
+	c, stop = e.Client()
+	c.Position("", nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, _, err := c.Go(ctx, uci.GoParams{Depth: 1}, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Go() = %v, expected %v", err, context.DeadlineExceeded)
+	}
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := c.IsReady(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("IsReady() = %v, expected %v", err, context.DeadlineExceeded)
+	}
+	stop()
+	<-c.Done()
+
+	// A slow engine can be timed out too.
+	e = &Engine{Delay: 200 * time.Millisecond}
+	c, stop = e.Client()
+	defer stop()
+	c.Position("", nil)
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, _, err := c.Go(ctx, uci.GoParams{Depth: 1}, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Go() = %v, expected %v", err, context.DeadlineExceeded)
+	}
+}
@@ -0,0 +1,110 @@
+package chess
+
+// hashKeys are the random numbers a Board hashes its positions with.
+type hashKeys struct {
+	pieces    [12 * 64]Hash // Indexed by Piece.HashIdx() plus the square's index.
+	castle    [4]Hash       // White O-O, white O-O-O, black O-O, black O-O-O.
+	ep        [8]Hash       // Indexed by the en passant target's file.
+	turn      Hash          // Hashed in when turnColor is to move.
+	turnColor Piece
+
+	// epCapture is set if the en passant file is only hashed when the side to
+	// move has a pawn that can capture on the target.
+	epCapture bool
+}
+
+// zobristKeys are our own keys, generated by zobrist_gen.go.
+var zobristKeys = func() *hashKeys {
+	k := &hashKeys{turn: zLookups[zBlack], turnColor: Black}
+	copy(k.pieces[:], zLookups[:])
+	copy(k.castle[:], zLookups[zWOO:zBOOO+1])
+	copy(k.ep[:], zLookups[zEP:])
+	return k
+}()
+
+// polyglotKeys are the keys used by Polyglot opening books.
+var polyglotKeys = func() *hashKeys {
+	k := &hashKeys{turn: polyglotRandom64[polyglotTurn], turnColor: White, epCapture: true}
+	for _, p := range []Piece{Pawn, Knight, Bishop, Rook, Queen, King} {
+		for _, color := range []Piece{White, Black} {
+			p := p | color
+			copy(k.pieces[p.HashIdx():], polyglotRandom64[64*polyglotKind(p):][:64])
+		}
+	}
+	copy(k.castle[:], polyglotRandom64[polyglotCastle:])
+	copy(k.ep[:], polyglotRandom64[polyglotEP:])
+	return k
+}()
+
+// HashScheme identifies the keys a Board hashes its positions with.
+type HashScheme uint8
+
+const (
+	ZobristHashing  HashScheme = iota // Our own keys, the default.
+	PolyglotHashing                   // The Polyglot keys.
+)
+
+// HashScheme returns the keys the Board hashes its positions with.
+func (b *Board) HashScheme() HashScheme {
+	if b.keys == polyglotKeys {
+		return PolyglotHashing
+	}
+	return ZobristHashing
+}
+
+// BoardOption configures a Board when it's created.
+type BoardOption func(*Board)
+
+// WithPolyglotHashing makes the Board hash its positions with the Polyglot
+// keys, so ZHash matches the keys in Polyglot opening books.
+func WithPolyglotHashing() BoardOption {
+	return func(b *Board) {
+		b.keys = polyglotKeys
+	}
+}
+
+// castleHash returns the hash of the board's castle state.
+func (k *hashKeys) castleHash(b *Board) (v Hash) {
+	for i, can := range []bool{b.state.wOO, b.state.wOOO, b.state.bOO, b.state.bOOO} {
+		if can {
+			v ^= k.castle[i]
+		}
+	}
+	return v
+}
+
+// epHash returns the hash of the board's en passant target.
+func (k *hashKeys) epHash(b *Board) Hash {
+	if b.state.epTarget == InvalidCoord || k.epCapture && !b.epCapturePossible() {
+		return 0
+	}
+	return k.ep[b.state.epTarget.FileIdx()]
+}
+
+// turnHash returns the hash of the side to move.
+func (k *hashKeys) turnHash(b *Board) Hash {
+	if b.state.turn == k.turnColor {
+		return k.turn
+	}
+	return 0
+}
+
+// pawnHash computes the hash of the board's pawns from scratch.
+func (k *hashKeys) pawnHash(b *Board) (h Hash) {
+	for i, p := range b.state.spaces {
+		if p.IsPawn() {
+			h ^= k.pieces[p.HashIdx()+i]
+		}
+	}
+	return h
+}
+
+// hash computes the hash of the board from scratch.
+func (k *hashKeys) hash(b *Board) (h Hash) {
+	for i, p := range b.state.spaces {
+		if p != Empty {
+			h ^= k.pieces[p.HashIdx()+i]
+		}
+	}
+	return h ^ k.castleHash(b) ^ k.epHash(b) ^ k.turnHash(b)
+}
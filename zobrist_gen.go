@@ -16,7 +16,7 @@ import (
 
 var seed = flag.Int64("seed", 99, "the seed for the random number generator")
 
-var header = `package main
+var header = `package chess
 // Code generated by go generate. DO NOT EDIT.
 
 type Hash uint64
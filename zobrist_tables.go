@@ -1,4 +1,4 @@
-package main
+package chess
 
 // Code generated by go generate. DO NOT EDIT.
 